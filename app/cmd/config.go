@@ -0,0 +1,52 @@
+/*
+Copyright © 2022 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Batyachelly/goBoard/internal/goboard"
+
+	"github.com/spf13/cobra"
+)
+
+// configCmd groups the configuration commands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration and list every invalid setting",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := goboard.ValidateConfig(configPath(cmd)); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "config is valid")
+
+		return nil
+	},
+}
+
+// configPrintCmd represents the config print command
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective configuration with secrets redacted",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+
+		return goboard.PrintConfig(configPath(cmd), format, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configPrintCmd)
+
+	configPrintCmd.Flags().StringP("format", "f", "yaml", "Output format: yaml, toml or env")
+}
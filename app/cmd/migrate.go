@@ -5,6 +5,10 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+
 	"github.com/Batyachelly/goBoard/internal/goboard"
 
 	"github.com/spf13/cobra"
@@ -14,11 +18,121 @@ import (
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Run migrations",
-	Run: func(cmd *cobra.Command, args []string) {
-		goboard.Migrate()
+	Long:  "Run migrations up to the latest version, or control them with the subcommands.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return goboard.MigrateTo(configPath(cmd), goboard.Latest(), false, cmd.OutOrStdout())
+	},
+}
+
+// migrateStatusCmd represents the migrate status command
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show applied and pending migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return goboard.MigrateStatus(configPath(cmd), cmd.OutOrStdout())
+	},
+}
+
+// migrateUpCmd represents the migrate up command
+var migrateUpCmd = &cobra.Command{
+	Use:   "up [steps]",
+	Short: "Apply pending migrations, all of them unless a step count is given",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := goboard.Latest()
+
+		if len(args) == 1 {
+			n, err := parseVersion(args[0])
+			if err != nil {
+				return err
+			}
+
+			target = goboard.Steps(n)
+		}
+
+		return goboard.MigrateTo(configPath(cmd), target, dryRun(cmd), cmd.OutOrStdout())
+	},
+}
+
+// migrateDownCmd represents the migrate down command
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [steps]",
+	Short: "Revert the last migration, or the given number of them",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n := int32(1)
+
+		if len(args) == 1 {
+			var err error
+
+			if n, err = parseVersion(args[0]); err != nil {
+				return err
+			}
+		}
+
+		return goboard.MigrateTo(configPath(cmd), goboard.Steps(-n), dryRun(cmd), cmd.OutOrStdout())
+	},
+}
+
+// migrateToCmd represents the migrate to command
+var migrateToCmd = &cobra.Command{
+	Use:   "to <version>",
+	Short: "Migrate up or down to the given version",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := parseVersion(args[0])
+		if err != nil {
+			return err
+		}
+
+		return goboard.MigrateTo(configPath(cmd), goboard.Version(v), dryRun(cmd), cmd.OutOrStdout())
+	},
+}
+
+// migratePlanCmd represents the migrate plan command
+var migratePlanCmd = &cobra.Command{
+	Use:   "plan [version]",
+	Short: "Print the SQL that migrating to the version, the latest by default, would run",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := goboard.Latest()
+
+		if len(args) == 1 {
+			v, err := parseVersion(args[0])
+			if err != nil {
+				return err
+			}
+
+			target = goboard.Version(v)
+		}
+
+		return goboard.MigrateTo(configPath(cmd), target, true, cmd.OutOrStdout())
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateStatusCmd, migrateUpCmd, migrateDownCmd, migrateToCmd, migratePlanCmd)
+
+	for _, c := range []*cobra.Command{migrateUpCmd, migrateDownCmd, migrateToCmd} {
+		c.Flags().Bool("dry-run", false, "Print the SQL instead of running it")
+	}
+}
+
+var errInvalidArg = errors.New("invalid argument")
+
+func dryRun(cmd *cobra.Command) bool {
+	v, _ := cmd.Flags().GetBool("dry-run")
+
+	return v
+}
+
+func parseVersion(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid number %q: %w", s, errInvalidArg)
+	}
+
+	return int32(v), nil
 }
@@ -14,6 +14,19 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "goBoard",
 	Short: "GoBoard application root",
+
+	SilenceUsage: true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringP("config", "c", "",
+		"Path to a YAML or TOML config file, overridden by environment variables (default $GOBOARD_CONFIG)")
+}
+
+func configPath(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("config")
+
+	return path
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -14,10 +14,10 @@ import (
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Run goBoard server",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		migrate, _ := cmd.Flags().GetBool("migrate")
 
-		goboard.Serve(migrate)
+		return goboard.Serve(configPath(cmd), migrate)
 	},
 }
 
@@ -0,0 +1,375 @@
+// Package cache keeps recently read boards and threads in process memory.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type Config struct {
+	// Size is the most boards, board settings and threads kept.
+	Size int
+	// TTL bounds how stale an entry gets when the data is changed by
+	// something other than this process.
+	TTL time.Duration
+}
+
+const (
+	boardListKind = iota
+	boardKind
+	settingsKind
+	threadKind
+	messageKind
+)
+
+type key struct {
+	kind     int
+	boardID  uint64
+	threadID uint64
+}
+
+// location is where a message posted through the cache lives, kept so that
+// changes to it need not query its thread.
+type location struct {
+	boardID  uint64
+	threadID uint64
+}
+
+type databaser struct {
+	next database.Databaser
+
+	mu      sync.Mutex
+	entries *lru
+	posted  *lru
+	// epoch changes on every invalidation. A read started in an earlier
+	// epoch may have seen the old data, so its result is not stored.
+	epoch uint64
+
+	group singleflight.Group
+}
+
+// NewDatabaser caches the board list, boards, board settings and threads.
+// Concurrent misses of one entry share a single query, and writes drop the
+// entries they change. Returned values are copies, free to modify.
+func NewDatabaser(next database.Databaser, cfg Config) database.Databaser {
+	return &databaser{
+		next:    next,
+		entries: newLRU(cfg.Size, cfg.TTL),
+		posted:  newLRU(cfg.Size, cfg.TTL),
+	}
+}
+
+// fetchTimeout bounds a query shared by the callers of one entry, which
+// is not cancelled along with any single one of them.
+const fetchTimeout = 10 * time.Second
+
+// load returns the cached value for k or fetches it, once for all the
+// concurrent callers. The fetch runs with the values of the first caller's
+// context but not its cancellation, so a caller going away does not fail
+// the others; each caller only waits for as long as its own context allows.
+func (d *databaser) load(ctx context.Context, k key, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	d.mu.Lock()
+
+	if value, ok := d.entries.get(k); ok {
+		d.mu.Unlock()
+
+		return value, nil
+	}
+
+	epoch := d.epoch
+
+	d.mu.Unlock()
+
+	flight := fmt.Sprintf("%d/%d/%d/%d", k.kind, k.boardID, k.threadID, epoch)
+
+	result := d.group.DoChan(flight, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(detached{ctx}, fetchTimeout)
+		defer cancel()
+
+		value, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		d.mu.Lock()
+		if d.epoch == epoch {
+			d.entries.set(k, value)
+		}
+		d.mu.Unlock()
+
+		return value, nil
+	})
+
+	select {
+	case r := <-result:
+		return r.Val, r.Err //nolint:wrapcheck
+	case <-ctx.Done():
+		return nil, fmt.Errorf("cache wait for fetch: %w", ctx.Err())
+	}
+}
+
+// detached carries the values of a context, such as the trace, without its
+// deadline and cancellation.
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detached) Done() <-chan struct{} { return nil }
+
+func (detached) Err() error { return nil }
+
+func (d *databaser) invalidate(match func(k key) bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.epoch++
+	d.entries.removeIf(match)
+}
+
+// invalidateThread drops the thread and the board listing it.
+func (d *databaser) invalidateThread(boardID, threadID uint64) {
+	d.invalidate(func(k key) bool {
+		return k.boardID == boardID && (k.kind == boardKind || k.kind == threadKind && k.threadID == threadID)
+	})
+}
+
+// invalidateBoard drops the board and all of its threads, for changes to
+// a message of an unknown thread.
+func (d *databaser) invalidateBoard(boardID uint64) {
+	d.invalidate(func(k key) bool {
+		return k.boardID == boardID && (k.kind == boardKind || k.kind == threadKind)
+	})
+}
+
+func (d *databaser) invalidateAll() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.epoch++
+	d.entries.clear()
+}
+
+func (d *databaser) remember(messageID, boardID, threadID uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.posted.set(key{kind: messageKind, threadID: messageID}, location{boardID: boardID, threadID: threadID})
+}
+
+func (d *databaser) lookup(messageID uint64) (location, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	value, ok := d.posted.get(key{kind: messageKind, threadID: messageID})
+	if !ok {
+		return location{}, false
+	}
+
+	return value.(location), true //nolint:forcetypeassert
+}
+
+// threadOf finds the thread of a message about to change, preferring the
+// messages posted through the cache over a query.
+func (d *databaser) threadOf(ctx context.Context, boardID, messageID uint64) (uint64, bool) {
+	if loc, ok := d.lookup(messageID); ok && loc.boardID == boardID {
+		return loc.threadID, true
+	}
+
+	message, err := d.next.GetMessage(ctx, boardID, messageID)
+	if err != nil {
+		return 0, false
+	}
+
+	return message.ThreadID, true
+}
+
+func (d *databaser) Migrate() error {
+	err := d.next.Migrate()
+	d.invalidateAll()
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) GetBoardList(ctx context.Context) (models.BoardList, error) {
+	value, err := d.load(ctx, key{kind: boardListKind}, func(ctx context.Context) (interface{}, error) {
+		return d.next.GetBoardList(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(models.BoardList{}, value.(models.BoardList)...), nil //nolint:forcetypeassert
+}
+
+func (d *databaser) GetBoard(ctx context.Context, boardID uint64) (*models.Board, error) {
+	value, err := d.load(ctx, key{kind: boardKind, boardID: boardID}, func(ctx context.Context) (interface{}, error) {
+		return d.next.GetBoard(ctx, boardID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	board := *value.(*models.Board) //nolint:forcetypeassert
+	board.Threads = copyMessages(board.Threads)
+
+	return &board, nil
+}
+
+func (d *databaser) GetBoardSettings(ctx context.Context, boardID uint64) (*models.BoardSettings, error) {
+	value, err := d.load(ctx, key{kind: settingsKind, boardID: boardID}, func(ctx context.Context) (interface{}, error) {
+		return d.next.GetBoardSettings(ctx, boardID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	settings := *value.(*models.BoardSettings) //nolint:forcetypeassert
+
+	return &settings, nil
+}
+
+func (d *databaser) GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error) {
+	value, err := d.load(ctx, key{kind: threadKind, boardID: boardID, threadID: threadID}, func(ctx context.Context) (interface{}, error) {
+		return d.next.GetThread(ctx, boardID, threadID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copyMessages(value.(models.MessageList)), nil //nolint:forcetypeassert
+}
+
+func (d *databaser) GetMessage(ctx context.Context, boardID, messageID uint64) (*models.Message, error) {
+	return d.next.GetMessage(ctx, boardID, messageID) //nolint:wrapcheck
+}
+
+func (d *databaser) PostThread(ctx context.Context, thread *models.Message, posterID database.PosterIDFunc) (uint64, uint64, error) {
+	id, threadID, err := d.next.PostThread(ctx, thread, posterID)
+	if err != nil {
+		return 0, 0, err //nolint:wrapcheck
+	}
+
+	d.remember(id, thread.BoardID, threadID)
+	d.invalidateThread(thread.BoardID, threadID)
+
+	return id, threadID, nil
+}
+
+func (d *databaser) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
+	id, err := d.next.PostMessage(ctx, message)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	d.remember(id, message.BoardID, message.ThreadID)
+	d.invalidateThread(message.BoardID, message.ThreadID)
+
+	return id, nil
+}
+
+func (d *databaser) BumpThread(ctx context.Context, boardID, threadID uint64) error {
+	err := d.next.BumpThread(ctx, boardID, threadID)
+
+	// Bumping only reorders the board.
+	d.invalidate(func(k key) bool {
+		return k.kind == boardKind && k.boardID == boardID
+	})
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) DeleteMessage(ctx context.Context, boardID, messageID uint64) error {
+	threadID, found := d.threadOf(ctx, boardID, messageID)
+
+	err := d.next.DeleteMessage(ctx, boardID, messageID)
+
+	if found {
+		d.invalidateThread(boardID, threadID)
+	} else {
+		d.invalidateBoard(boardID)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) DeleteMessageContent(ctx context.Context, boardID, messageID uint64) error {
+	threadID, found := d.threadOf(ctx, boardID, messageID)
+
+	err := d.next.DeleteMessageContent(ctx, boardID, messageID)
+
+	if found {
+		d.invalidateThread(boardID, threadID)
+	} else {
+		d.invalidateBoard(boardID)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// PurgeMessageIPs changes no cached data, addresses are not listed.
+func (d *databaser) PurgeMessageIPs(ctx context.Context, before time.Time) (int64, error) {
+	return d.next.PurgeMessageIPs(ctx, before) //nolint:wrapcheck
+}
+
+func (d *databaser) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	return d.next.PostReport(ctx, report) //nolint:wrapcheck
+}
+
+func (d *databaser) GetReport(ctx context.Context, reportID uint64) (*models.Report, error) {
+	return d.next.GetReport(ctx, reportID) //nolint:wrapcheck
+}
+
+func (d *databaser) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	return d.next.GetReportList(ctx, status) //nolint:wrapcheck
+}
+
+func (d *databaser) ResolveReport(ctx context.Context, reportID uint64, status int) error {
+	return d.next.ResolveReport(ctx, reportID, status) //nolint:wrapcheck
+}
+
+func (d *databaser) ActOnReport(ctx context.Context, report *models.Report) error {
+	threadID, found := d.threadOf(ctx, report.BoardID, report.MessageID)
+
+	err := d.next.ActOnReport(ctx, report)
+
+	if found {
+		d.invalidateThread(report.BoardID, threadID)
+	} else {
+		d.invalidateBoard(report.BoardID)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	return d.next.PostBan(ctx, ban) //nolint:wrapcheck
+}
+
+func (d *databaser) GetBanList(ctx context.Context) (models.BanList, error) {
+	return d.next.GetBanList(ctx) //nolint:wrapcheck
+}
+
+func (d *databaser) GetActiveBans(ctx context.Context, ip, ipHash string, boardID uint64) (models.BanList, error) {
+	return d.next.GetActiveBans(ctx, ip, ipHash, boardID) //nolint:wrapcheck
+}
+
+func (d *databaser) DeleteBan(ctx context.Context, banID uint64) error {
+	return d.next.DeleteBan(ctx, banID) //nolint:wrapcheck
+}
+
+func copyMessages(messages models.MessageList) models.MessageList {
+	if messages == nil {
+		return nil
+	}
+
+	return append(models.MessageList{}, messages...)
+}
@@ -0,0 +1,325 @@
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/cache"
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/databasetest"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = cache.Config{Size: 16, TTL: time.Minute}
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	databasetest.Run(t, func(t *testing.T, boards ...databasetest.Board) database.Databaser {
+		t.Helper()
+
+		ds := memory.NewDatabaseService(memory.Config{})
+
+		for _, b := range boards {
+			id := ds.CreateBoard(b.Title, b.Settings)
+			if b.Deleted {
+				ds.DeleteBoard(id)
+			}
+		}
+
+		return cache.NewDatabaser(ds, testConfig)
+	})
+}
+
+func TestDatabaser_GetThread(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	thread := models.MessageList{{ID: 1, Title: "op", Sage: true}}
+
+	ds := &mocks.Databaser{}
+	ds.On("GetThread", mock.Anything, uint64(1), uint64(2)).Once().Return(thread, nil)
+
+	db := cache.NewDatabaser(ds, testConfig)
+
+	got, err := db.GetThread(ctx, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, thread, got)
+
+	// Callers may modify what they get without touching the cache.
+	got[0].Sage = false
+
+	got, err = db.GetThread(ctx, 1, 2)
+	require.NoError(t, err)
+	require.True(t, got[0].Sage)
+
+	ds.AssertExpectations(t)
+}
+
+func TestDatabaser_Coalescing(t *testing.T) {
+	t.Parallel()
+
+	ds := &mocks.Databaser{}
+	ds.On("GetBoard", mock.Anything, uint64(1)).Once().After(50*time.Millisecond).Return(&models.Board{ID: 1}, nil)
+
+	db := cache.NewDatabaser(ds, testConfig)
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := db.GetBoard(context.Background(), 1)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	ds.AssertExpectations(t)
+}
+
+func TestDatabaser_CoalescingCanceled(t *testing.T) {
+	t.Parallel()
+
+	ds := &mocks.Databaser{}
+	fetchErr := make(chan error, 1)
+
+	ds.On("GetBoard", mock.Anything, uint64(1)).Once().After(100*time.Millisecond).Run(func(args mock.Arguments) {
+		fetchErr <- args.Get(0).(context.Context).Err()
+	}).Return(&models.Board{ID: 1}, nil)
+
+	db := cache.NewDatabaser(ds, testConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	leader := make(chan error, 1)
+
+	go func() {
+		_, err := db.GetBoard(ctx, 1)
+		leader <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	follower := make(chan error, 1)
+
+	go func() {
+		board, err := db.GetBoard(context.Background(), 1)
+		if err == nil && board.ID != 1 {
+			err = fmt.Errorf("unexpected board %d", board.ID)
+		}
+		follower <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	require.ErrorIs(t, <-leader, context.Canceled)
+	require.NoError(t, <-follower)
+	require.NoError(t, <-fetchErr)
+
+	ds.AssertExpectations(t)
+}
+
+func TestDatabaser_Errors(t *testing.T) {
+	t.Parallel()
+
+	ds := &mocks.Databaser{}
+	ds.On("GetBoard", mock.Anything, uint64(1)).Twice().Return(nil, database.ErrNotFound)
+
+	db := cache.NewDatabaser(ds, testConfig)
+
+	for i := 0; i < 2; i++ {
+		_, err := db.GetBoard(context.Background(), 1)
+		require.ErrorIs(t, err, database.ErrNotFound, "errors are not cached")
+	}
+
+	ds.AssertExpectations(t)
+}
+
+func TestDatabaser_TTL(t *testing.T) {
+	t.Parallel()
+
+	ds := &mocks.Databaser{}
+	ds.On("GetBoardList", mock.Anything).Twice().Return(models.BoardList{{ID: 1}}, nil)
+
+	db := cache.NewDatabaser(ds, cache.Config{Size: 16, TTL: 10 * time.Millisecond})
+
+	_, err := db.GetBoardList(context.Background())
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = db.GetBoardList(context.Background())
+	require.NoError(t, err)
+
+	ds.AssertExpectations(t)
+}
+
+func TestDatabaser_Size(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	ds := &mocks.Databaser{}
+	ds.On("GetBoardSettings", mock.Anything, uint64(1)).Twice().Return(&models.BoardSettings{}, nil)
+	ds.On("GetBoardSettings", mock.Anything, uint64(2)).Once().Return(&models.BoardSettings{}, nil)
+
+	db := cache.NewDatabaser(ds, cache.Config{Size: 1, TTL: time.Minute})
+
+	for _, boardID := range []uint64{1, 2, 1} {
+		_, err := db.GetBoardSettings(ctx, boardID)
+		require.NoError(t, err)
+	}
+
+	ds.AssertExpectations(t)
+}
+
+// TestDatabaser_Invalidation checks which cached reads every write drops:
+// after the write, the reads marked reloaded must query again.
+func TestDatabaser_Invalidation(t *testing.T) {
+	t.Parallel()
+
+	type reads struct {
+		board, thread, otherThread, otherBoard, boardList bool
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(ds *mocks.Databaser)
+		write    func(ctx context.Context, db database.Databaser) error
+		reloaded reads
+	}{
+		{
+			name: "post thread",
+			setup: func(ds *mocks.Databaser) {
+				ds.On("PostThread", mock.Anything, mock.Anything, mock.Anything).Return(uint64(10), uint64(3), nil)
+			},
+			write: func(ctx context.Context, db database.Databaser) error {
+				_, _, err := db.PostThread(ctx, &models.Message{BoardID: 1}, nil)
+
+				return err
+			},
+			reloaded: reads{board: true},
+		},
+		{
+			name: "post message",
+			setup: func(ds *mocks.Databaser) {
+				ds.On("PostMessage", mock.Anything, mock.Anything).Return(uint64(10), nil)
+			},
+			write: func(ctx context.Context, db database.Databaser) error {
+				_, err := db.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: 1})
+
+				return err
+			},
+			reloaded: reads{board: true, thread: true},
+		},
+		{
+			name: "bump",
+			setup: func(ds *mocks.Databaser) {
+				ds.On("BumpThread", mock.Anything, uint64(1), uint64(1)).Return(nil)
+			},
+			write: func(ctx context.Context, db database.Databaser) error {
+				return db.BumpThread(ctx, 1, 1)
+			},
+			reloaded: reads{board: true},
+		},
+		{
+			name: "delete message",
+			setup: func(ds *mocks.Databaser) {
+				ds.On("GetMessage", mock.Anything, uint64(1), uint64(5)).Return(&models.Message{ID: 5, BoardID: 1, ThreadID: 1}, nil)
+				ds.On("DeleteMessage", mock.Anything, uint64(1), uint64(5)).Return(nil)
+			},
+			write: func(ctx context.Context, db database.Databaser) error {
+				return db.DeleteMessage(ctx, 1, 5)
+			},
+			reloaded: reads{board: true, thread: true},
+		},
+		{
+			name: "delete content of a message of an unknown thread",
+			setup: func(ds *mocks.Databaser) {
+				ds.On("GetMessage", mock.Anything, uint64(1), uint64(5)).Return(nil, database.ErrNotFound)
+				ds.On("DeleteMessageContent", mock.Anything, uint64(1), uint64(5)).Return(nil)
+			},
+			write: func(ctx context.Context, db database.Databaser) error {
+				return db.DeleteMessageContent(ctx, 1, 5)
+			},
+			reloaded: reads{board: true, thread: true, otherThread: true},
+		},
+		{
+			name: "report",
+			setup: func(ds *mocks.Databaser) {
+				ds.On("PostReport", mock.Anything, mock.Anything).Return(uint64(1), nil)
+			},
+			write: func(ctx context.Context, db database.Databaser) error {
+				_, err := db.PostReport(ctx, &models.Report{BoardID: 1, MessageID: 1})
+
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+
+			times := func(reloaded bool) int {
+				if reloaded {
+					return 2
+				}
+
+				return 1
+			}
+
+			ds := &mocks.Databaser{}
+			ds.On("GetBoard", mock.Anything, uint64(1)).Times(times(tt.reloaded.board)).Return(&models.Board{ID: 1}, nil)
+			ds.On("GetThread", mock.Anything, uint64(1), uint64(1)).Times(times(tt.reloaded.thread)).Return(models.MessageList{}, nil)
+			ds.On("GetThread", mock.Anything, uint64(1), uint64(2)).Times(times(tt.reloaded.otherThread)).Return(models.MessageList{}, nil)
+			ds.On("GetBoard", mock.Anything, uint64(2)).Times(times(tt.reloaded.otherBoard)).Return(&models.Board{ID: 2}, nil)
+			ds.On("GetBoardList", mock.Anything).Times(times(tt.reloaded.boardList)).Return(models.BoardList{}, nil)
+			tt.setup(ds)
+
+			db := cache.NewDatabaser(ds, testConfig)
+
+			readAll := func() {
+				_, err := db.GetBoard(ctx, 1)
+				require.NoError(t, err)
+				_, err = db.GetThread(ctx, 1, 1)
+				require.NoError(t, err)
+				_, err = db.GetThread(ctx, 1, 2)
+				require.NoError(t, err)
+				_, err = db.GetBoard(ctx, 2)
+				require.NoError(t, err)
+				_, err = db.GetBoardList(ctx)
+				require.NoError(t, err)
+			}
+
+			readAll()
+			require.NoError(t, tt.write(ctx, db))
+			readAll()
+
+			ds.AssertExpectations(t)
+		})
+	}
+}
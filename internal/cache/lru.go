@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// lru holds at most size entries, dropping the least recently used one
+// when full and every entry once it is older than ttl. It is not safe
+// for concurrent use.
+type lru struct {
+	size  int
+	ttl   time.Duration
+	items map[key]*list.Element
+	order *list.List
+}
+
+type entry struct {
+	key     key
+	value   interface{}
+	expires time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{
+		size:  size,
+		ttl:   ttl,
+		items: make(map[key]*list.Element),
+		order: list.New(),
+	}
+}
+
+func (c *lru) get(k key) (interface{}, bool) {
+	el, ok := c.items[k]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry) //nolint:forcetypeassert
+
+	if time.Now().After(e.expires) {
+		c.removeElement(el)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return e.value, true
+}
+
+func (c *lru) set(k key, value interface{}) {
+	expires := time.Now().Add(c.ttl)
+
+	if el, ok := c.items[k]; ok {
+		e := el.Value.(*entry) //nolint:forcetypeassert
+		e.value = value
+		e.expires = expires
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.items[k] = c.order.PushFront(&entry{key: k, value: value, expires: expires})
+
+	if c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// removeIf drops every entry whose key matches.
+func (c *lru) removeIf(match func(k key) bool) {
+	for k, el := range c.items {
+		if match(k) {
+			c.removeElement(el)
+		}
+	}
+}
+
+func (c *lru) clear() {
+	c.items = make(map[key]*list.Element)
+	c.order.Init()
+}
+
+func (c *lru) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry).key) //nolint:forcetypeassert
+}
@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// Cache configures the in-process cache of boards and threads. With several
+// instances behind a load balancer, another instance sees its own writes
+// immediately and those of the others after at most TTL.
+type Cache struct {
+	Enabled bool          `env:"CACHE_ENABLED" envDefault:"true" yaml:"enabled" toml:"enabled"`
+	Size    int           `env:"CACHE_SIZE"    envDefault:"1024" yaml:"size"    toml:"size"`
+	TTL     time.Duration `env:"CACHE_TTL"     envDefault:"5s"   yaml:"ttl"     toml:"ttl"`
+}
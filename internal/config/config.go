@@ -2,30 +2,109 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
-	"github.com/caarlos0/env"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
 )
 
+// FileEnv names the environment variable holding the config file path.
+const FileEnv = "GOBOARD_CONFIG"
+
 type Config struct {
-	HTTP     HTTP
-	Postgres Postgres
-	General  General
+	HTTP       HTTP       `yaml:"http"       toml:"http"`
+	GRPC       GRPC       `yaml:"grpc"       toml:"grpc"`
+	Storage    Storage    `yaml:"storage"    toml:"storage"`
+	Postgres   Postgres   `yaml:"postgres"   toml:"postgres"`
+	SQLite     SQLite     `yaml:"sqlite"     toml:"sqlite"`
+	General    General    `yaml:"general"    toml:"general"`
+	Moderation Moderation `yaml:"moderation" toml:"moderation"`
+	Privacy    Privacy    `yaml:"privacy"    toml:"privacy"`
+	Posting    Posting    `yaml:"posting"    toml:"posting"`
+	Metrics    Metrics    `yaml:"metrics"    toml:"metrics"`
+	Tracing    Tracing    `yaml:"tracing"    toml:"tracing"`
+	Cache      Cache      `yaml:"cache"      toml:"cache"`
+}
+
+// ParseConfig loads the configuration with Load from the process
+// environment and validates it. An empty path falls back to GOBOARD_CONFIG.
+func ParseConfig(path string) (*Config, error) {
+	return parse(path, (*Config).Validate)
+}
+
+// ParseStorageConfig is ParseConfig validating only the storage settings.
+func ParseStorageConfig(path string) (*Config, error) {
+	return parse(path, (*Config).ValidateStorage)
+}
+
+func parse(path string, validate func(*Config) error) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(FileEnv)
+	}
+
+	cfg, err := Load(path, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
-func ParseConfig() (*Config, error) {
+// Load builds the configuration from the defaults, then the YAML or TOML
+// file at path if given, then the environment. Every variable NAME may
+// instead be given as NAME_FILE, naming a file holding the value.
+func Load(path string, lookup func(string) (string, bool)) (*Config, error) {
 	cfg := new(Config)
 
-	if err := env.Parse(&cfg.General); err != nil {
-		return nil, fmt.Errorf("parse general config: %w", err)
+	if err := setDefaults(cfg); err != nil {
+		return nil, err
 	}
 
-	if err := env.Parse(&cfg.HTTP); err != nil {
-		return nil, fmt.Errorf("parse http config: %w", err)
+	if path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return nil, err
+		}
 	}
 
-	if err := env.Parse(&cfg.Postgres); err != nil {
-		return nil, fmt.Errorf("parse postgres config: %w", err)
+	if err := applyEnv(cfg, lookup); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
 }
+
+func decodeFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(strings.NewReader(string(content)))
+		dec.KnownFields(true)
+
+		if err := dec.Decode(cfg); err != nil {
+			return fmt.Errorf("parse yaml config %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(content), cfg)
+		if err != nil {
+			return fmt.Errorf("parse toml config %s: %w", path, err)
+		}
+
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse toml config %s: key %q: %w", path, undecoded[0].String(), ErrUnknownKey)
+		}
+	default:
+		return fmt.Errorf("config file %s: extension %q: %w", path, ext, ErrUnknownFormat)
+	}
+
+	return nil
+}
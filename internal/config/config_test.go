@@ -0,0 +1,271 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func validEnv() map[string]string {
+	return map[string]string{
+		"HTTP_ADDR":     ":8080",
+		"POSTGRES_HOST": "localhost",
+		"POSTGRES_USER": "admin",
+		"POSTGRES_DB":   "gboard",
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	yamlFile := writeFile(t, "goboard.yaml", `
+http:
+  addr: ":9000"
+  write_timeout: 30s
+postgres:
+  host: db
+  port: 6543
+moderation:
+  tokens: ["alice:secret"]
+`)
+
+	tomlFile := writeFile(t, "goboard.toml", `
+[http]
+addr = ":9000"
+write_timeout = "30s"
+
+[postgres]
+host = "db"
+port = 6543
+`)
+
+	passwordFile := writeFile(t, "password", "s3cret\n")
+
+	tests := []struct {
+		name    string
+		path    string
+		env     map[string]string
+		check   func(t *testing.T, cfg *config.Config)
+		wantErr error
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, 15*time.Second, cfg.HTTP.WriteTimeout)
+				require.Equal(t, 5432, cfg.Postgres.Port)
+				require.Equal(t, "version", cfg.Postgres.VersionTable)
+				require.True(t, cfg.Metrics.Enabled)
+			},
+		},
+		{
+			name: "yaml file under env",
+			path: yamlFile,
+			env:  map[string]string{"HTTP_ADDR": ":8080"},
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, ":8080", cfg.HTTP.Addr)
+				require.Equal(t, 30*time.Second, cfg.HTTP.WriteTimeout)
+				require.Equal(t, 15*time.Second, cfg.HTTP.ReadTimeout)
+				require.Equal(t, "db", cfg.Postgres.Host)
+				require.Equal(t, 6543, cfg.Postgres.Port)
+				require.Equal(t, []string{"alice:secret"}, cfg.Moderation.Tokens)
+			},
+		},
+		{
+			name: "toml file",
+			path: tomlFile,
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, ":9000", cfg.HTTP.Addr)
+				require.Equal(t, 30*time.Second, cfg.HTTP.WriteTimeout)
+				require.Equal(t, 6543, cfg.Postgres.Port)
+			},
+		},
+		{
+			name: "file suffix",
+			env:  map[string]string{"POSTGRES_PASSWORD_FILE": passwordFile},
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, "s3cret", cfg.Postgres.Password)
+			},
+		},
+		{
+			name: "deprecated name",
+			env:  map[string]string{"POSTGRESS_SSL": "disable"},
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, "disable", cfg.Postgres.SSLMode)
+			},
+		},
+		{
+			name: "env list",
+			env:  map[string]string{"MODERATOR_TOKENS": "alice:a, bob:b"},
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, []string{"alice:a", "bob:b"}, cfg.Moderation.Tokens)
+			},
+		},
+		{
+			name:    "unknown key",
+			path:    writeFile(t, "bad.toml", "[http]\nadress = \":1\"\n"),
+			wantErr: config.ErrUnknownKey,
+		},
+		{
+			name:    "unknown extension",
+			path:    writeFile(t, "goboard.json", "{}"),
+			wantErr: config.ErrUnknownFormat,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.Load(tt.path, lookup(tt.env))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.Load("", lookup(validEnv()))
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	cfg, err = config.Load("", lookup(map[string]string{
+		"STORAGE":               "memory",
+		"HTTP_ADDR":             ":8080",
+		"HTTP_CORS_ORIGINS":     "https://frontend.example.com, http://localhost:3000",
+		"HTTP_CORS_CREDENTIALS": "true",
+	}))
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate(), "postgres settings are not needed for the memory backend")
+
+	cfg, err = config.Load("", lookup(map[string]string{
+		"STORAGE":          "memory",
+		"HTTP_LISTENER":    "unix",
+		"HTTP_SOCKET":      "/run/goboard/goboard.sock",
+		"HTTP_SOCKET_MODE": "0600",
+
+		"HTTP_TRUSTED_PROXIES": "10.0.0.0/8, 192.0.2.1, 2001:db8::/32",
+	}))
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate(), "the address is not needed for a unix socket")
+
+	env := validEnv()
+	env["METRICS_ADDR"] = env["HTTP_ADDR"]
+
+	cfg, err = config.Load("", lookup(env))
+	require.NoError(t, err)
+
+	var metricsErr *config.ValidationError
+	require.ErrorAs(t, cfg.Validate(), &metricsErr)
+	require.Equal(t, "METRICS_ADDR", metricsErr.Fields[0].Env)
+
+	cfg, err = config.Load("", lookup(map[string]string{
+		"POSTGRES_HOST":     "localhost",
+		"POSTGRES_USER":     "admin",
+		"POSTGRES_DB":       "gboard",
+		"POSTGRES_PORT":     "70000",
+		"LOG_LEVEL":         "LOUD",
+		"IP_ENCRYPTION_KEY": "abc",
+
+		"MAX_THREAD_WAITERS":    "-1",
+		"HTTP_TLS_CERT":         "/etc/goboard/cert.pem",
+		"HTTP_TRUSTED_PROXIES":  "10.0.0.0/8, proxy.local",
+		"GRPC_ENABLED":          "true",
+		"GRPC_MAX_RECV_SIZE":    "0",
+		"HTTP_CORS_ORIGINS":     "*, https://frontend.example.com/app",
+		"HTTP_CORS_CREDENTIALS": "true",
+	}))
+	require.NoError(t, err)
+
+	err = cfg.Validate()
+	require.ErrorIs(t, err, config.ErrInvalid)
+
+	var verr *config.ValidationError
+	require.ErrorAs(t, err, &verr)
+
+	envs := make([]string, 0, len(verr.Fields))
+	for _, f := range verr.Fields {
+		envs = append(envs, f.Env)
+	}
+
+	require.Equal(t, []string{"HTTP_ADDR", "HTTP_TRUSTED_PROXIES", "HTTP_TLS_KEY", "HTTP_CORS_ORIGINS", "HTTP_CORS_CREDENTIALS", "GRPC_MAX_RECV_SIZE", "POSTGRES_PORT", "LOG_LEVEL", "IP_ENCRYPTION_KEY", "IP_HASH_KEY", "MAX_THREAD_WAITERS"}, envs)
+}
+
+func TestConfig_ValidateStorage(t *testing.T) {
+	t.Parallel()
+
+	env := validEnv()
+	delete(env, "HTTP_ADDR")
+
+	cfg, err := config.Load("", lookup(env))
+	require.NoError(t, err)
+	require.Error(t, cfg.Validate())
+	require.NoError(t, cfg.ValidateStorage(), "the HTTP settings are not needed to migrate")
+
+	env["POSTGRES_MIGRATIONS_PATH"] = filepath.Join(t.TempDir(), "missing")
+	delete(env, "POSTGRES_HOST")
+
+	cfg, err = config.Load("", lookup(env))
+	require.NoError(t, err)
+
+	var verr *config.ValidationError
+	require.ErrorAs(t, cfg.ValidateStorage(), &verr)
+
+	envs := make([]string, 0, len(verr.Fields))
+	for _, f := range verr.Fields {
+		envs = append(envs, f.Env)
+	}
+
+	require.Equal(t, []string{"POSTGRES_HOST", "POSTGRES_MIGRATIONS_PATH"}, envs)
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	t.Parallel()
+
+	env := validEnv()
+	env["POSTGRES_PASSWORD"] = "s3cret"
+	env["MODERATOR_TOKENS"] = "alice:token"
+
+	cfg, err := config.Load("", lookup(env))
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, cfg.Redacted().Write(buf, "env"))
+
+	require.Contains(t, buf.String(), `POSTGRES_PASSWORD="[REDACTED]"`)
+	require.Contains(t, buf.String(), `MODERATOR_TOKENS="alice:[REDACTED]"`)
+	require.Contains(t, buf.String(), `IP_HASH_KEY=""`)
+	require.NotContains(t, buf.String(), "s3cret")
+	require.Equal(t, []string{"alice:token"}, cfg.Moderation.Tokens)
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// deprecatedEnv maps variables to the misspelled names they replaced,
+// still read when the new name is not set.
+var deprecatedEnv = map[string]string{
+	"POSTGRES_SSL":           "POSTGRESS_SSL",
+	"POSTGRES_VERSION_TABLE": "POSTGRESS_VERSION_TABLE",
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a leaf setting of Config, found by walking its sections.
+type field struct {
+	value  reflect.Value
+	tag    reflect.StructTag
+	key    string
+	envKey string
+}
+
+func fields(cfg *Config) []field {
+	var fs []field
+
+	sections := reflect.ValueOf(cfg).Elem()
+
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		sectionKey := sections.Type().Field(i).Tag.Get("yaml")
+
+		for j := 0; j < section.NumField(); j++ {
+			sf := section.Type().Field(j)
+
+			fs = append(fs, field{
+				value:  section.Field(j),
+				tag:    sf.Tag,
+				key:    sectionKey + "." + sf.Tag.Get("yaml"),
+				envKey: sf.Tag.Get("env"),
+			})
+		}
+	}
+
+	return fs
+}
+
+func setDefaults(cfg *Config) error {
+	for _, f := range fields(cfg) {
+		def, ok := f.tag.Lookup("envDefault")
+		if !ok {
+			continue
+		}
+
+		if err := setField(f, def); err != nil {
+			return fmt.Errorf("default of %s: %w", f.envKey, err)
+		}
+	}
+
+	return nil
+}
+
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	for _, f := range fields(cfg) {
+		value, ok, err := lookupEnv(f.envKey, lookup)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setField(f, value); err != nil {
+			return fmt.Errorf("parse %s: %w", f.envKey, err)
+		}
+	}
+
+	return nil
+}
+
+func lookupEnv(name string, lookup func(string) (string, bool)) (string, bool, error) {
+	if value, ok := lookup(name); ok {
+		return value, true, nil
+	}
+
+	if path, ok := lookup(name + "_FILE"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("read %s_FILE: %w", name, err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), true, nil
+	}
+
+	if old, ok := deprecatedEnv[name]; ok {
+		return lookupEnv(old, lookup)
+	}
+
+	return "", false, nil
+}
+
+func setField(f field, value string) error {
+	v := f.value
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("parse duration: %w", err)
+		}
+
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("parse int: %w", err)
+		}
+
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("parse bool: %w", err)
+		}
+
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		sep := f.tag.Get("envSeparator")
+		if sep == "" {
+			sep = ","
+		}
+
+		var items []string
+
+		for _, item := range strings.Split(value, sep) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("type %s: %w", v.Type(), ErrUnsupportedType)
+	}
+
+	return nil
+}
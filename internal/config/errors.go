@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownFormat   = errors.New("unknown config format")
+	ErrUnknownKey      = errors.New("unknown config key")
+	ErrUnsupportedType = errors.New("unsupported config field type")
+	ErrInvalid         = errors.New("invalid config")
+)
+
+// FieldError describes one invalid setting by its variable and file key.
+type FieldError struct {
+	Env     string
+	Key     string
+	Message string
+}
+
+func (e FieldError) String() string {
+	return e.Env + " (" + e.Key + "): " + e.Message
+}
+
+// ValidationError lists every invalid setting found by Validate.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Fields)+1)
+	lines = append(lines, "invalid config:")
+
+	for _, f := range e.Fields {
+		lines = append(lines, "  "+f.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
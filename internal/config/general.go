@@ -1,5 +1,9 @@
 package config
 
 type General struct {
-	LogLevel string `env:"LOG_LEVEL" envDefault:"DEBUG"`
+	LogLevel string `env:"LOG_LEVEL" envDefault:"DEBUG" yaml:"log_level" toml:"log_level"`
+	// LogBackend is "logrus" or "zap".
+	LogBackend string `env:"LOG_BACKEND" envDefault:"logrus" yaml:"log_backend" toml:"log_backend"`
+	// LogFormat is "text" or "json".
+	LogFormat string `env:"LOG_FORMAT" envDefault:"text" yaml:"log_format" toml:"log_format"`
 }
@@ -0,0 +1,9 @@
+package config
+
+// GRPC configures the gRPC API, served next to the HTTP one on its own
+// address. MaxRecvSize caps request messages in bytes.
+type GRPC struct {
+	Enabled     bool   `env:"GRPC_ENABLED"       envDefault:"false" yaml:"enabled"       toml:"enabled"`
+	Addr        string `env:"GRPC_ADDR"          envDefault:":9090" yaml:"addr"          toml:"addr"`
+	MaxRecvSize int    `env:"GRPC_MAX_RECV_SIZE" envDefault:"65536" yaml:"max_recv_size" toml:"max_recv_size"`
+}
@@ -3,7 +3,54 @@ package config
 import "time"
 
 type HTTP struct {
-	Addr         string        `env:"HTTP_ADDR"`
-	WriteTimeout time.Duration `env:"HTTP_WRITE_TIMEOUT" envDefault:"15s"`
-	ReadTimeout  time.Duration `env:"HTTP_READ_TIMEOUT"  envDefault:"15s"`
+	Addr            string        `env:"HTTP_ADDR"                                  yaml:"addr"              toml:"addr"`
+	WriteTimeout    time.Duration `env:"HTTP_WRITE_TIMEOUT"     envDefault:"15s"    yaml:"write_timeout"     toml:"write_timeout"`
+	ReadTimeout     time.Duration `env:"HTTP_READ_TIMEOUT"      envDefault:"15s"    yaml:"read_timeout"      toml:"read_timeout"`
+	ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT"  envDefault:"15s"    yaml:"shutdown_timeout"  toml:"shutdown_timeout"`
+	HealthTimeout   time.Duration `env:"HTTP_HEALTH_TIMEOUT"    envDefault:"2s"     yaml:"health_timeout"    toml:"health_timeout"`
+	ReadinessDrain  time.Duration `env:"HTTP_READINESS_DRAIN"   envDefault:"5s"     yaml:"readiness_drain"   toml:"readiness_drain"`
+	Compress        bool          `env:"HTTP_COMPRESS"          envDefault:"true"   yaml:"compress"          toml:"compress"`
+	CompressMinSize int           `env:"HTTP_COMPRESS_MIN_SIZE" envDefault:"1024"   yaml:"compress_min_size" toml:"compress_min_size"`
+
+	// Listener is "tcp" to listen on Addr, "unix" on the Socket path or
+	// "systemd" on the socket passed by systemd socket activation.
+	Listener string `env:"HTTP_LISTENER" envDefault:"tcp" yaml:"listener" toml:"listener"`
+	// Socket is the path of the Unix socket, created with the octal SocketMode.
+	Socket     string `env:"HTTP_SOCKET"                        yaml:"socket"      toml:"socket"`
+	SocketMode string `env:"HTTP_SOCKET_MODE" envDefault:"0660" yaml:"socket_mode" toml:"socket_mode"`
+
+	// TLSCert and TLSKey are PEM files, TLS with HTTP/2 is on when they are
+	// set. Changed files are picked up after at most TLSReloadInterval.
+	TLSCert           string        `env:"HTTP_TLS_CERT"                             yaml:"tls_cert"            toml:"tls_cert"`
+	TLSKey            string        `env:"HTTP_TLS_KEY"                              yaml:"tls_key"             toml:"tls_key"`
+	TLSReloadInterval time.Duration `env:"HTTP_TLS_RELOAD_INTERVAL" envDefault:"30s" yaml:"tls_reload_interval" toml:"tls_reload_interval"`
+	// H2C serves HTTP/2 without TLS, for traffic behind a proxy.
+	H2C bool `env:"HTTP_H2C" envDefault:"false" yaml:"h2c" toml:"h2c"`
+	// TrustedProxies are the addresses and CIDR networks of the reverse
+	// proxies whose X-Forwarded-For and X-Real-IP give the client address.
+	// Proxies on the Unix socket are always trusted.
+	TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES" envSeparator:"," yaml:"trusted_proxies" toml:"trusted_proxies"`
+
+	// MaxBodySize caps request bodies in bytes, PostMaxBodySize those of
+	// new threads and replies.
+	MaxBodySize     int `env:"HTTP_MAX_BODY_SIZE"      envDefault:"4096"  yaml:"max_body_size"      toml:"max_body_size"`
+	PostMaxBodySize int `env:"HTTP_POST_MAX_BODY_SIZE" envDefault:"65536" yaml:"post_max_body_size" toml:"post_max_body_size"`
+
+	// CORSOrigins are the origins allowed to call the API from a browser,
+	// "*" for any. CORS is off when empty.
+	CORSOrigins []string `env:"HTTP_CORS_ORIGINS" envSeparator:"," yaml:"cors_origins" toml:"cors_origins"`
+	// CORSMethods and CORSHeaders are the methods and request headers
+	// allowed in cross-origin requests.
+	CORSMethods []string `env:"HTTP_CORS_METHODS" envDefault:"GET,POST,DELETE" envSeparator:"," yaml:"cors_methods" toml:"cors_methods"`
+	CORSHeaders []string `env:"HTTP_CORS_HEADERS" envDefault:"Content-Type,Authorization" envSeparator:"," yaml:"cors_headers" toml:"cors_headers"`
+	// CORSCredentials lets cross-origin requests carry cookies and the
+	// Authorization header, it cannot be combined with the "*" origin.
+	CORSCredentials bool `env:"HTTP_CORS_CREDENTIALS" envDefault:"false" yaml:"cors_credentials" toml:"cors_credentials"`
+	// CORSMaxAge is how long browsers may cache a preflight response.
+	CORSMaxAge time.Duration `env:"HTTP_CORS_MAX_AGE" envDefault:"10m" yaml:"cors_max_age" toml:"cors_max_age"`
+
+	// CSP is the Content-Security-Policy of the API.
+	CSP string `env:"HTTP_CSP" envDefault:"default-src 'none'; frame-ancestors 'none'" yaml:"csp" toml:"csp"`
+	// DocsCSP is the Content-Security-Policy of the Swagger UI, which runs scripts.
+	DocsCSP string `env:"HTTP_DOCS_CSP" envDefault:"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'" yaml:"docs_csp" toml:"docs_csp"`
 }
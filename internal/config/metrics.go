@@ -0,0 +1,9 @@
+package config
+
+// Metrics configures the Prometheus endpoint. With Addr set it is served on
+// a listener of its own instead of the public HTTP one.
+type Metrics struct {
+	Enabled bool   `env:"METRICS_ENABLED" envDefault:"true"     yaml:"enabled" toml:"enabled"`
+	Path    string `env:"METRICS_PATH"    envDefault:"/metrics" yaml:"path"    toml:"path"`
+	Addr    string `env:"METRICS_ADDR"    envDefault:""         yaml:"addr"    toml:"addr"`
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type Moderation struct {
+	// Tokens is a list of "name:token" pairs, one per moderator.
+	Tokens       []string      `env:"MODERATOR_TOKENS"   envSeparator:","   yaml:"tokens"        toml:"tokens"        secret:"true"`
+	ReportLimit  int           `env:"REPORT_RATE_LIMIT"  envDefault:"5"     yaml:"report_limit"  toml:"report_limit"`
+	ReportPeriod time.Duration `env:"REPORT_RATE_PERIOD" envDefault:"1m"    yaml:"report_period" toml:"report_period"`
+}
@@ -1,11 +1,14 @@
 package config
 
 type Postgres struct {
-	Host         string `env:"POSTGRES_HOST"`
-	Port         int    `env:"POSTGRES_PORT"`
-	User         string `env:"POSTGRES_USER"`
-	Password     string `env:"POSTGRES_PASSWORD"`
-	DB           string `env:"POSTGRES_DB"`
-	SSLMode      string `env:"POSTGRESS_SSL"`
-	VersionTable string `env:"POSTGRESS_VERSION_TABLE" envDefault:"version"`
+	Host         string `env:"POSTGRES_HOST"                                  yaml:"host"          toml:"host"`
+	Port         int    `env:"POSTGRES_PORT"          envDefault:"5432"       yaml:"port"          toml:"port"`
+	User         string `env:"POSTGRES_USER"                                  yaml:"user"          toml:"user"`
+	Password     string `env:"POSTGRES_PASSWORD"                              yaml:"password"      toml:"password"      secret:"true"`
+	DB           string `env:"POSTGRES_DB"                                    yaml:"db"            toml:"db"`
+	SSLMode      string `env:"POSTGRES_SSL"           envDefault:"prefer"     yaml:"ssl_mode"      toml:"ssl_mode"`
+	VersionTable string `env:"POSTGRES_VERSION_TABLE" envDefault:"version"    yaml:"version_table" toml:"version_table"`
+
+	// MigrationsPath overrides the migrations embedded in the binary with a directory.
+	MigrationsPath string `env:"POSTGRES_MIGRATIONS_PATH" yaml:"migrations_path" toml:"migrations_path"`
 }
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type Posting struct {
+	// DeletionWindow is how long authors may delete their own posts, zero disables it.
+	DeletionWindow time.Duration `env:"DELETION_WINDOW"    envDefault:"24h"  yaml:"deletion_window"    toml:"deletion_window"`
+	// MaxThreadWaiters caps the requests long-polling threads at once, zero leaves them unlimited.
+	MaxThreadWaiters int `env:"MAX_THREAD_WAITERS" envDefault:"1000" yaml:"max_thread_waiters" toml:"max_thread_waiters"`
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Redacted returns a copy of the config with every secret that is set
+// replaced by a placeholder. Moderator names are kept, their tokens are not.
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.Moderation.Tokens = append([]string(nil), c.Moderation.Tokens...)
+
+	for _, f := range fields(&out) {
+		if f.tag.Get("secret") != "true" {
+			continue
+		}
+
+		switch f.value.Kind() { //nolint:exhaustive
+		case reflect.String:
+			if f.value.String() != "" {
+				f.value.SetString(redacted)
+			}
+		case reflect.Slice:
+			for i := 0; i < f.value.Len(); i++ {
+				item := f.value.Index(i)
+				name := strings.SplitN(item.String(), ":", 2)[0]
+				item.SetString(name + ":" + redacted)
+			}
+		}
+	}
+
+	return &out
+}
+
+// Write encodes the config as "yaml", "toml" or "env" variable assignments.
+func (c *Config) Write(w io.Writer, format string) error {
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("encode yaml config: %w", err)
+		}
+
+		return enc.Close() //nolint:wrapcheck
+	case "toml":
+		if err := toml.NewEncoder(w).Encode(c); err != nil {
+			return fmt.Errorf("encode toml config: %w", err)
+		}
+
+		return nil
+	case "env":
+		for _, f := range fields(c) {
+			if _, err := fmt.Fprintf(w, "%s=%q\n", f.envKey, envValue(f)); err != nil {
+				return fmt.Errorf("write env config: %w", err)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("print format %q: %w", format, ErrUnknownFormat)
+	}
+}
+
+func envValue(f field) string {
+	if f.value.Type() == durationType {
+		return f.value.Interface().(fmt.Stringer).String()
+	}
+
+	if f.value.Kind() == reflect.Slice {
+		sep := f.tag.Get("envSeparator")
+		if sep == "" {
+			sep = ","
+		}
+
+		return strings.Join(f.value.Interface().([]string), sep)
+	}
+
+	return fmt.Sprint(f.value.Interface())
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type Privacy struct {
+	IPHashKey       string        `env:"IP_HASH_KEY"                             yaml:"ip_hash_key"       toml:"ip_hash_key"       secret:"true"`
+	IPEncryptionKey string        `env:"IP_ENCRYPTION_KEY"                       yaml:"ip_encryption_key" toml:"ip_encryption_key" secret:"true"`
+	IPRetention     time.Duration `env:"IP_RETENTION"      envDefault:"720h"     yaml:"ip_retention"      toml:"ip_retention"`
+	IPPurgeInterval time.Duration `env:"IP_PURGE_INTERVAL" envDefault:"1h"       yaml:"ip_purge_interval" toml:"ip_purge_interval"`
+}
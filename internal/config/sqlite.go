@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+type SQLite struct {
+	// Path is the database file, created on first start.
+	Path        string        `env:"SQLITE_PATH"         envDefault:"goboard.db" yaml:"path"         toml:"path"`
+	BusyTimeout time.Duration `env:"SQLITE_BUSY_TIMEOUT" envDefault:"5s"         yaml:"busy_timeout" toml:"busy_timeout"`
+}
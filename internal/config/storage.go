@@ -0,0 +1,9 @@
+package config
+
+type Storage struct {
+	// Backend is "postgres", "sqlite" or "memory", the latter keeping
+	// everything in process memory until exit.
+	Backend string `env:"STORAGE" envDefault:"postgres" yaml:"backend" toml:"backend"`
+	// MemoryBoards are the titles of the boards the memory backend starts with.
+	MemoryBoards []string `env:"STORAGE_MEMORY_BOARDS" envDefault:"General" envSeparator:"," yaml:"memory_boards" toml:"memory_boards"`
+}
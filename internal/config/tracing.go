@@ -0,0 +1,12 @@
+package config
+
+// Tracing configures the span exporter. OTLPEndpoint is the host:port of an
+// OTLP/HTTP collector, reached without TLS when OTLPInsecure is set.
+type Tracing struct {
+	// Exporter is empty to disable tracing, "otlp", "stdout" or "file".
+	Exporter     string `env:"TRACING_EXPORTER"                                  yaml:"exporter"      toml:"exporter"`
+	File         string `env:"TRACING_FILE"          envDefault:"spans.json"     yaml:"file"          toml:"file"`
+	ServiceName  string `env:"TRACING_SERVICE_NAME"  envDefault:"goboard"        yaml:"service_name"  toml:"service_name"`
+	OTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4318" yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+	OTLPInsecure bool   `env:"TRACING_OTLP_INSECURE" envDefault:"false"          yaml:"otlp_insecure" toml:"otlp_insecure"`
+}
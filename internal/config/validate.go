@@ -0,0 +1,214 @@
+package config
+
+import (
+	"encoding/hex"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+)
+
+const (
+	maxPort          = 65535
+	encryptionKeyLen = 32
+)
+
+type validator struct {
+	cfg    *Config
+	fields []FieldError
+}
+
+// Validate checks every setting and returns a *ValidationError listing all
+// the invalid ones.
+func (c *Config) Validate() error {
+	v := &validator{cfg: c}
+
+	v.validateListener(&c.HTTP)
+	v.check(c.HTTP.WriteTimeout >= 0, &c.HTTP.WriteTimeout, "must not be negative")
+	v.check(c.HTTP.ReadTimeout >= 0, &c.HTTP.ReadTimeout, "must not be negative")
+	v.check(c.HTTP.ShutdownTimeout > 0, &c.HTTP.ShutdownTimeout, "must be positive")
+	v.check(c.HTTP.HealthTimeout > 0, &c.HTTP.HealthTimeout, "must be positive")
+	v.check(c.HTTP.ReadinessDrain >= 0, &c.HTTP.ReadinessDrain, "must not be negative")
+	v.check(c.HTTP.CompressMinSize >= 0, &c.HTTP.CompressMinSize, "must not be negative")
+	v.check(c.HTTP.MaxBodySize > 0, &c.HTTP.MaxBodySize, "must be positive")
+	v.check(c.HTTP.PostMaxBodySize > 0, &c.HTTP.PostMaxBodySize, "must be positive")
+	v.validateCORS(&c.HTTP)
+
+	if c.GRPC.Enabled {
+		v.check(c.GRPC.Addr != "", &c.GRPC.Addr, "must be set")
+		v.check(c.GRPC.MaxRecvSize > 0, &c.GRPC.MaxRecvSize, "must be positive")
+	}
+
+	v.validateStorage(c)
+
+	v.oneOf(c.General.LogLevel, &c.General.LogLevel,
+		logger.DebugLevel, logger.InfoLevel, logger.WarnLevel, logger.ErrorLevel, logger.PanicLevel, logger.FatalLevel)
+	v.oneOf(c.General.LogBackend, &c.General.LogBackend, "logrus", "zap")
+	v.oneOf(c.General.LogFormat, &c.General.LogFormat, "text", "json")
+
+	for _, token := range c.Moderation.Tokens {
+		parts := strings.SplitN(token, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			v.add(&c.Moderation.Tokens, "entries must be name:token pairs")
+
+			break
+		}
+	}
+
+	v.check(c.Moderation.ReportLimit >= 0, &c.Moderation.ReportLimit, "must not be negative")
+	v.check(c.Moderation.ReportPeriod >= 0, &c.Moderation.ReportPeriod, "must not be negative")
+
+	if c.Privacy.IPEncryptionKey != "" {
+		key, err := hex.DecodeString(c.Privacy.IPEncryptionKey)
+		v.check(err == nil && len(key) == encryptionKeyLen, &c.Privacy.IPEncryptionKey, "must be 64 hex characters")
+	}
+
+	v.check((c.Privacy.IPHashKey == "") == (c.Privacy.IPEncryptionKey == ""), &c.Privacy.IPHashKey,
+		"must be set together with IP_ENCRYPTION_KEY")
+	v.check(c.Privacy.IPRetention > 0, &c.Privacy.IPRetention, "must be positive")
+	v.check(c.Privacy.IPPurgeInterval > 0, &c.Privacy.IPPurgeInterval, "must be positive")
+
+	v.check(c.Posting.DeletionWindow >= 0, &c.Posting.DeletionWindow, "must not be negative")
+	v.check(c.Posting.MaxThreadWaiters >= 0, &c.Posting.MaxThreadWaiters, "must not be negative")
+
+	v.check(strings.HasPrefix(c.Metrics.Path, "/"), &c.Metrics.Path, "must start with /")
+	v.check(c.Metrics.Addr == "" || c.Metrics.Addr != c.HTTP.Addr, &c.Metrics.Addr, "must differ from HTTP_ADDR")
+
+	v.oneOf(c.Tracing.Exporter, &c.Tracing.Exporter, "", "otlp", "stdout", "file")
+	v.check(c.Tracing.Exporter != "file" || c.Tracing.File != "", &c.Tracing.File, "must be set for the file exporter")
+	v.check(c.Tracing.Exporter != "otlp" || c.Tracing.OTLPEndpoint != "", &c.Tracing.OTLPEndpoint, "must be set for the otlp exporter")
+
+	if c.Cache.Enabled {
+		v.check(c.Cache.Size > 0, &c.Cache.Size, "must be positive")
+		v.check(c.Cache.TTL > 0, &c.Cache.TTL, "must be positive")
+	}
+
+	if len(v.fields) > 0 {
+		return &ValidationError{Fields: v.fields}
+	}
+
+	return nil
+}
+
+// ValidateStorage checks only the storage backend and migrations settings,
+// all the migrate commands use.
+func (c *Config) ValidateStorage() error {
+	v := &validator{cfg: c}
+
+	v.validateStorage(c)
+
+	if len(v.fields) > 0 {
+		return &ValidationError{Fields: v.fields}
+	}
+
+	return nil
+}
+
+func (v *validator) validateStorage(c *Config) {
+	v.oneOf(c.Storage.Backend, &c.Storage.Backend, "postgres", "sqlite", "memory")
+
+	if c.Storage.Backend == "postgres" {
+		v.check(c.Postgres.Host != "", &c.Postgres.Host, "must be set")
+		v.check(c.Postgres.Port > 0 && c.Postgres.Port <= maxPort, &c.Postgres.Port, "must be a port number")
+		v.check(c.Postgres.User != "", &c.Postgres.User, "must be set")
+		v.check(c.Postgres.DB != "", &c.Postgres.DB, "must be set")
+		v.oneOf(c.Postgres.SSLMode, &c.Postgres.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+		v.check(c.Postgres.VersionTable != "", &c.Postgres.VersionTable, "must be set")
+
+		if c.Postgres.MigrationsPath != "" {
+			info, err := os.Stat(c.Postgres.MigrationsPath)
+			v.check(err == nil && info.IsDir(), &c.Postgres.MigrationsPath, "must be a directory")
+		}
+	}
+
+	if c.Storage.Backend == "sqlite" {
+		v.check(c.SQLite.Path != "", &c.SQLite.Path, "must be set")
+		v.check(c.SQLite.BusyTimeout > 0, &c.SQLite.BusyTimeout, "must be positive")
+	}
+}
+
+func (v *validator) validateListener(h *HTTP) {
+	v.oneOf(h.Listener, &h.Listener, "tcp", "unix", "systemd")
+
+	switch h.Listener {
+	case "tcp":
+		v.check(h.Addr != "", &h.Addr, "must be set")
+	case "unix":
+		v.check(h.Socket != "", &h.Socket, "must be set")
+
+		mode, err := strconv.ParseUint(h.SocketMode, 8, 32)
+		v.check(err == nil && mode <= 0o777, &h.SocketMode, "must be an octal file mode like 0660")
+	}
+
+	for _, proxy := range h.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			v.add(&h.TrustedProxies, "entries must be IP addresses or CIDR networks")
+
+			break
+		}
+	}
+
+	v.check((h.TLSCert == "") == (h.TLSKey == ""), &h.TLSKey, "must be set together with HTTP_TLS_CERT")
+	v.check(h.TLSReloadInterval > 0, &h.TLSReloadInterval, "must be positive")
+}
+
+func (v *validator) validateCORS(h *HTTP) {
+	anyOrigin := false
+
+	for _, origin := range h.CORSOrigins {
+		if origin == "*" {
+			anyOrigin = true
+
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+			v.add(&h.CORSOrigins, `entries must be "*" or origins like https://example.com`)
+
+			break
+		}
+	}
+
+	if len(h.CORSOrigins) > 0 {
+		v.check(len(h.CORSMethods) > 0, &h.CORSMethods, "must be set when CORS is on")
+	}
+
+	v.check(!anyOrigin || !h.CORSCredentials, &h.CORSCredentials, `cannot be used with the "*" origin`)
+	v.check(h.CORSMaxAge >= 0, &h.CORSMaxAge, "must not be negative")
+}
+
+func (v *validator) check(ok bool, ptr interface{}, message string) {
+	if !ok {
+		v.add(ptr, message)
+	}
+}
+
+func (v *validator) oneOf(value string, ptr interface{}, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	quoted := make([]string, len(allowed))
+	for i, a := range allowed {
+		quoted[i] = `"` + a + `"`
+	}
+
+	v.add(ptr, "must be one of "+strings.Join(quoted, ", "))
+}
+
+// add records an error for the field that ptr points to.
+func (v *validator) add(ptr interface{}, message string) {
+	for _, f := range fields(v.cfg) {
+		if f.value.Addr().Interface() == ptr {
+			v.fields = append(v.fields, FieldError{Env: f.envKey, Key: f.key, Message: message})
+
+			return
+		}
+	}
+}
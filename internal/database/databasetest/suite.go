@@ -0,0 +1,728 @@
+// Package databasetest is a conformance suite for database.Databaser
+// backends. Every backend runs it from its own tests, so they stay
+// interchangeable:
+//
+//	func TestConformance(t *testing.T) {
+//		databasetest.Run(t, func(t *testing.T, boards ...databasetest.Board) database.Databaser {
+//			return newBackendWith(t, boards)
+//		})
+//	}
+package databasetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Board is a board the backend must hold before a test starts. Boards have
+// no Databaser methods, so backends create them by their own means.
+type Board struct {
+	Title    string
+	Settings models.BoardSettings
+	// Deleted boards are stored soft-deleted.
+	Deleted bool
+}
+
+// NewBackend returns an empty backend holding the boards with IDs 1 to
+// len(boards) in order. It is called once per test and the tests run one
+// at a time, so a backend on a shared database may reset it every call.
+type NewBackend func(t *testing.T, boards ...Board) database.Databaser
+
+// Run runs every conformance test against the backends made by newBackend.
+func Run(t *testing.T, newBackend NewBackend) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, newBackend NewBackend)
+	}{
+		{name: "BoardList", test: testBoardList},
+		{name: "Board", test: testBoard},
+		{name: "BoardSettings", test: testBoardSettings},
+		{name: "Thread", test: testThread},
+		{name: "Message", test: testMessage},
+		{name: "DeleteMessage", test: testDeleteMessage},
+		{name: "Updated", test: testUpdated},
+		{name: "PurgeMessageIPs", test: testPurgeMessageIPs},
+		{name: "Reports", test: testReports},
+		{name: "ActOnReport", test: testActOnReport},
+		{name: "Bans", test: testBans},
+		{name: "ActiveBans", test: testActiveBans},
+		{name: "TimeZones", test: testTimeZones},
+		{name: "ConcurrentInserts", test: testConcurrentInserts},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newBackend)
+		})
+	}
+}
+
+var (
+	general = Board{Title: "General", Settings: models.BoardSettings{ShowSage: true}}
+	random  = Board{Title: "Random", Settings: models.BoardSettings{PosterIDs: true}}
+	deleted = Board{Title: "Deleted", Deleted: true}
+)
+
+// missingID is past the last row of every table in these tests.
+const missingID = 1000
+
+func testBoardList(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+
+	boards, err := newBackend(t).GetBoardList(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, boards)
+	require.Empty(t, boards)
+
+	boards, err = newBackend(t, general, deleted, random).GetBoardList(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.BoardList{{ID: 1, Title: "General"}, {ID: 3, Title: "Random"}}, boards)
+}
+
+func testBoard(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general, deleted, random)
+
+	board, err := db.GetBoard(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), board.ID)
+	require.Equal(t, "General", board.Title)
+	require.Empty(t, board.Threads)
+
+	for _, id := range []uint64{0, 2, missingID} {
+		_, err := db.GetBoard(ctx, id)
+		require.ErrorIs(t, err, database.ErrNotFound, "board %d", id)
+	}
+
+	older := postThread(t, db, 1, "older")
+	newer := postThread(t, db, 1, "newer")
+	hidden := postThread(t, db, 1, "deleted")
+	postThread(t, db, 3, "other board")
+
+	require.NoError(t, db.DeleteMessage(ctx, 1, hidden.id))
+
+	// Bumping moves the older thread above the newer one.
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, db.BumpThread(ctx, 1, older.threadID))
+
+	board, err = db.GetBoard(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{older.id, newer.id}, messageIDs(board.Threads))
+	require.Equal(t, "older", board.Threads[0].Title)
+	require.False(t, board.Threads[0].Created.IsZero())
+}
+
+func testBoardSettings(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general, deleted, random)
+
+	settings, err := db.GetBoardSettings(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, &general.Settings, settings)
+
+	settings, err = db.GetBoardSettings(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, &random.Settings, settings)
+
+	for _, id := range []uint64{0, 2, missingID} {
+		_, err := db.GetBoardSettings(ctx, id)
+		require.ErrorIs(t, err, database.ErrNotFound, "board %d", id)
+	}
+}
+
+func testThread(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general, random)
+
+	first := postThread(t, db, 1, "first")
+
+	reply, err := db.PostMessage(ctx, &models.Message{
+		BoardID: 1, ThreadID: first.threadID, Title: "reply", Text: "text", Content: "content", PosterID: "abcd", Sage: true,
+	})
+	require.NoError(t, err)
+	require.Greater(t, reply, first.id)
+
+	// Thread IDs are a sequence of their own, replies do not advance it.
+	second := postThread(t, db, 2, "second")
+	require.Greater(t, second.id, reply)
+	require.Greater(t, second.threadID, first.threadID)
+	require.NotEqual(t, first.threadID, second.threadID)
+
+	thread, err := db.GetThread(ctx, 1, first.threadID)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{first.id, reply}, messageIDs(thread))
+	require.Equal(t, models.Message{
+		ID:       reply,
+		Title:    "reply",
+		Text:     "text",
+		Content:  "content",
+		Created:  thread[1].Created,
+		PosterID: "abcd",
+		Sage:     true,
+		Updated:  thread[1].Updated,
+	}, thread[1])
+	require.False(t, thread[1].Created.IsZero())
+
+	// A thread is looked up on its own board only.
+	thread, err = db.GetThread(ctx, 2, first.threadID)
+	require.NoError(t, err)
+	require.NotNil(t, thread)
+	require.Empty(t, thread)
+
+	thread, err = db.GetThread(ctx, 1, missingID)
+	require.NoError(t, err)
+	require.NotNil(t, thread)
+	require.Empty(t, thread)
+
+	// A new thread starts without a poster ID and sage.
+	op, threadID, err := db.PostThread(ctx, &models.Message{BoardID: 1, Title: "op", PosterID: "abcd", Sage: true}, nil)
+	require.NoError(t, err)
+
+	thread, err = db.GetThread(ctx, 1, threadID)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{op}, messageIDs(thread))
+	require.Empty(t, thread[0].PosterID)
+	require.False(t, thread[0].Sage)
+
+	var posterThreadID uint64
+
+	op, threadID, err = db.PostThread(ctx, &models.Message{BoardID: 1, Title: "op"}, func(threadID uint64) (string, error) {
+		posterThreadID = threadID
+
+		return "efgh", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, threadID, posterThreadID, "the poster ID is made for the new thread")
+
+	thread, err = db.GetThread(ctx, 1, threadID)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{op}, messageIDs(thread))
+	require.Equal(t, "efgh", thread[0].PosterID)
+
+	// A failing poster ID fails the whole thread.
+	errPosterID := errors.New("poster id")
+
+	_, _, err = db.PostThread(ctx, &models.Message{BoardID: 1, Title: "failed"}, func(uint64) (string, error) {
+		return "", errPosterID
+	})
+	require.ErrorIs(t, err, errPosterID)
+
+	board, err := db.GetBoard(ctx, 1)
+	require.NoError(t, err)
+
+	for _, m := range board.Threads {
+		require.NotEqual(t, "failed", m.Title)
+	}
+}
+
+func testMessage(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general, random)
+
+	id, threadID, err := db.PostThread(ctx, &models.Message{
+		BoardID:      1,
+		Title:        "title",
+		Text:         "text",
+		Content:      "content",
+		IPHash:       "hash",
+		IPEncrypted:  []byte{1, 2, 3},
+		DeletionHash: "deletion",
+	}, nil)
+	require.NoError(t, err)
+
+	message, err := db.GetMessage(ctx, 1, id)
+	require.NoError(t, err)
+	require.Equal(t, &models.Message{
+		ID:           id,
+		BoardID:      1,
+		ThreadID:     threadID,
+		Title:        "title",
+		Text:         "text",
+		Content:      "content",
+		Created:      message.Created,
+		IPHash:       "hash",
+		IPEncrypted:  []byte{1, 2, 3},
+		DeletionHash: "deletion",
+	}, message)
+	require.False(t, message.Created.IsZero())
+
+	for _, tt := range []struct{ boardID, messageID uint64 }{{2, id}, {1, 0}, {1, missingID}} {
+		_, err := db.GetMessage(ctx, tt.boardID, tt.messageID)
+		require.ErrorIs(t, err, database.ErrNotFound, "message %d on board %d", tt.messageID, tt.boardID)
+	}
+
+	require.NoError(t, db.DeleteMessageContent(ctx, 1, id))
+
+	message, err = db.GetMessage(ctx, 1, id)
+	require.NoError(t, err)
+	require.Empty(t, message.Content)
+	require.Equal(t, "text", message.Text)
+}
+
+func testDeleteMessage(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general, random)
+
+	op := postThread(t, db, 1, "op")
+
+	reply, err := db.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: op.threadID, Title: "reply"})
+	require.NoError(t, err)
+
+	// Deleting on the wrong board is a no-op.
+	require.NoError(t, db.DeleteMessage(ctx, 2, reply))
+	require.NoError(t, db.DeleteMessage(ctx, 1, reply))
+
+	_, err = db.GetMessage(ctx, 1, reply)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	_, err = db.GetMessage(ctx, 1, op.id)
+	require.NoError(t, err)
+
+	thread, err := db.GetThread(ctx, 1, op.threadID)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{op.id}, messageIDs(thread))
+
+	board, err := db.GetBoard(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{op.id}, messageIDs(board.Threads))
+
+	// Deleting twice is not an error.
+	require.NoError(t, db.DeleteMessage(ctx, 1, reply))
+}
+
+// testUpdated checks that every change to a thread, including the ones
+// that hide a message, moves the update time of the thread and its board.
+func testUpdated(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	op := postThread(t, db, 1, "op")
+	other := postThread(t, db, 1, "other")
+
+	reply, err := db.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: op.threadID, Title: "reply", Content: "content"})
+	require.NoError(t, err)
+
+	reported, err := db.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: op.threadID, Title: "reported"})
+	require.NoError(t, err)
+
+	report := postReport(t, db, 1, reported)
+
+	updated := func() (time.Time, time.Time) {
+		thread, err := db.GetThread(ctx, 1, op.threadID)
+		require.NoError(t, err)
+
+		board, err := db.GetBoard(ctx, 1)
+		require.NoError(t, err)
+
+		for _, m := range thread {
+			require.Equal(t, thread[0].Updated, m.Updated, "message %d", m.ID)
+		}
+
+		return thread[0].Updated, board.Updated
+	}
+
+	changes := []struct {
+		name   string
+		change func() error
+	}{
+		{name: "reply", change: func() error {
+			_, err := db.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: op.threadID, Title: "another"})
+
+			return err
+		}},
+		{name: "delete content", change: func() error { return db.DeleteMessageContent(ctx, 1, reply) }},
+		{name: "delete", change: func() error { return db.DeleteMessage(ctx, 1, reply) }},
+		{name: "act on report", change: func() error { return db.ActOnReport(ctx, &models.Report{ID: report, BoardID: 1, MessageID: reported}) }},
+	}
+	for _, c := range changes {
+		threadBefore, boardBefore := updated()
+		require.False(t, threadBefore.IsZero())
+		require.False(t, boardBefore.IsZero())
+
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, c.change(), c.name)
+
+		threadAfter, boardAfter := updated()
+		require.True(t, threadAfter.After(threadBefore), c.name)
+		require.True(t, boardAfter.After(boardBefore), c.name)
+	}
+
+	// Other threads keep their time.
+	before, err := db.GetThread(ctx, 1, other.threadID)
+	require.NoError(t, err)
+
+	require.NoError(t, db.DeleteMessage(ctx, 1, op.id))
+
+	after, err := db.GetThread(ctx, 1, other.threadID)
+	require.NoError(t, err)
+	require.Equal(t, before[0].Updated, after[0].Updated)
+}
+
+func testPurgeMessageIPs(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	withIP, _, err := db.PostThread(ctx, &models.Message{BoardID: 1, IPHash: "hash", IPEncrypted: []byte{1}}, nil)
+	require.NoError(t, err)
+
+	_, _, err = db.PostThread(ctx, &models.Message{BoardID: 1, IPHash: "hash"}, nil)
+	require.NoError(t, err)
+
+	purged, err := db.PurgeMessageIPs(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged, "messages newer than the cutoff are kept")
+
+	purged, err = db.PurgeMessageIPs(ctx, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	message, err := db.GetMessage(ctx, 1, withIP)
+	require.NoError(t, err)
+	require.Nil(t, message.IPEncrypted)
+	require.Equal(t, "hash", message.IPHash, "address hashes outlive the addresses")
+
+	purged, err = db.PurgeMessageIPs(ctx, time.Now().Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged)
+}
+
+func testReports(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	reports, err := db.GetReportList(ctx, models.ReportOpen)
+	require.NoError(t, err)
+	require.NotNil(t, reports)
+	require.Empty(t, reports)
+
+	op := postThread(t, db, 1, "op")
+
+	first, err := db.PostReport(ctx, &models.Report{
+		BoardID: 1, MessageID: op.id, Reason: models.ReportReasonSpam, Comment: "comment", Status: models.ReportActioned,
+	})
+	require.NoError(t, err)
+
+	second, err := db.PostReport(ctx, &models.Report{BoardID: 1, MessageID: op.id, Reason: models.ReportReasonRules})
+	require.NoError(t, err)
+	require.Greater(t, second, first)
+
+	report, err := db.GetReport(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, &models.Report{
+		ID:        first,
+		BoardID:   1,
+		MessageID: op.id,
+		Reason:    models.ReportReasonSpam,
+		Comment:   "comment",
+		Status:    models.ReportOpen,
+		Created:   report.Created,
+	}, report, "new reports are open whatever the status given")
+	require.False(t, report.Created.IsZero())
+
+	for _, id := range []uint64{0, missingID} {
+		_, err := db.GetReport(ctx, id)
+		require.ErrorIs(t, err, database.ErrNotFound, "report %d", id)
+	}
+
+	reports, err = db.GetReportList(ctx, models.ReportOpen)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{first, second}, reportIDs(reports))
+
+	require.NoError(t, db.ResolveReport(ctx, first, models.ReportDismissed))
+	require.ErrorIs(t, db.ResolveReport(ctx, first, models.ReportActioned), database.ErrConflict,
+		"only open reports are resolved")
+
+	report, err = db.GetReport(ctx, first)
+	require.NoError(t, err)
+	require.Equal(t, models.ReportDismissed, report.Status)
+	require.NotNil(t, report.Resolved)
+
+	reports, err = db.GetReportList(ctx, models.ReportOpen)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{second}, reportIDs(reports))
+
+	reports, err = db.GetReportList(ctx, models.ReportDismissed)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{first}, reportIDs(reports))
+}
+
+func testActOnReport(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	reported := postThread(t, db, 1, "reported")
+	other := postThread(t, db, 1, "other")
+
+	dismissed := postReport(t, db, 1, reported.id)
+	open := postReport(t, db, 1, reported.id)
+	acted := postReport(t, db, 1, reported.id)
+	otherOpen := postReport(t, db, 1, other.id)
+
+	require.NoError(t, db.ResolveReport(ctx, dismissed, models.ReportDismissed))
+
+	report, err := db.GetReport(ctx, acted)
+	require.NoError(t, err)
+	require.NoError(t, db.ActOnReport(ctx, report))
+
+	_, err = db.GetMessage(ctx, 1, reported.id)
+	require.ErrorIs(t, err, database.ErrNotFound, "the reported message is deleted")
+
+	_, err = db.GetMessage(ctx, 1, other.id)
+	require.NoError(t, err)
+
+	reports, err := db.GetReportList(ctx, models.ReportActioned)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{open, acted}, reportIDs(reports), "only open reports are resolved")
+	require.NotNil(t, reports[0].Resolved)
+
+	reports, err = db.GetReportList(ctx, models.ReportDismissed)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{dismissed}, reportIDs(reports))
+
+	reports, err = db.GetReportList(ctx, models.ReportOpen)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{otherOpen}, reportIDs(reports))
+
+	// Acting on a report resolved meanwhile changes nothing.
+	require.ErrorIs(t, db.ActOnReport(ctx, &models.Report{ID: dismissed, BoardID: 1, MessageID: other.id}), database.ErrConflict)
+
+	_, err = db.GetMessage(ctx, 1, other.id)
+	require.NoError(t, err, "a conflicting action deletes nothing")
+}
+
+func testBans(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	bans, err := db.GetBanList(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, bans)
+	require.Empty(t, bans)
+
+	expired := time.Now().Add(-24 * time.Hour)
+	expires := time.Now().Add(24 * time.Hour)
+
+	global := postBan(t, db, &models.Ban{Network: "192.0.2.0/24", Reason: "spam", Moderator: "admin"})
+	onBoard := postBan(t, db, &models.Ban{BoardID: 1, IPHash: "hash", Expires: &expires})
+	postBan(t, db, &models.Ban{Network: "198.51.100.0/24", Expires: &expired})
+	removed := postBan(t, db, &models.Ban{Network: "203.0.113.0/24"})
+
+	require.NoError(t, db.DeleteBan(ctx, removed))
+
+	for _, id := range []uint64{0, removed, missingID} {
+		require.ErrorIs(t, db.DeleteBan(ctx, id), database.ErrNotFound, "ban %d", id)
+	}
+
+	bans, err = db.GetBanList(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{global, onBoard}, banIDs(bans))
+
+	require.Equal(t, models.Ban{
+		ID:        global,
+		Network:   "192.0.2.0/24",
+		Reason:    "spam",
+		Moderator: "admin",
+		Created:   bans[0].Created,
+	}, bans[0])
+	require.False(t, bans[0].Created.IsZero())
+
+	require.Equal(t, uint64(1), bans[1].BoardID)
+	require.Equal(t, "hash", bans[1].IPHash)
+	require.Empty(t, bans[1].Network)
+	require.NotNil(t, bans[1].Expires)
+}
+
+func testActiveBans(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general, random)
+	expired := time.Now().Add(-24 * time.Hour)
+
+	network := postBan(t, db, &models.Ban{Network: "192.0.2.0/24"})
+	onBoard := postBan(t, db, &models.Ban{BoardID: 2, Network: "198.51.100.7/32"})
+	hash := postBan(t, db, &models.Ban{IPHash: "hash"})
+	postBan(t, db, &models.Ban{Network: "203.0.113.0/24", Expires: &expired})
+	removed := postBan(t, db, &models.Ban{Network: "2001:db8::/32"})
+
+	require.NoError(t, db.DeleteBan(ctx, removed))
+
+	tests := []struct {
+		name    string
+		ip      string
+		ipHash  string
+		boardID uint64
+		want    []uint64
+	}{
+		{name: "network", ip: "192.0.2.10", boardID: 1, want: []uint64{network}},
+		{name: "outside network", ip: "192.0.3.10", boardID: 1, want: []uint64{}},
+		{name: "other board", ip: "198.51.100.7", boardID: 1, want: []uint64{}},
+		{name: "board", ip: "198.51.100.7", boardID: 2, want: []uint64{onBoard}},
+		{name: "hash", ipHash: "hash", boardID: 1, want: []uint64{hash}},
+		{name: "network and hash", ip: "192.0.2.10", ipHash: "hash", boardID: 1, want: []uint64{network, hash}},
+		{name: "no address", boardID: 1, want: []uint64{}},
+		{name: "expired", ip: "203.0.113.1", boardID: 1, want: []uint64{}},
+		{name: "deleted", ip: "2001:db8::1", boardID: 1, want: []uint64{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bans, err := db.GetActiveBans(ctx, tt.ip, tt.ipHash, tt.boardID)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, banIDs(bans))
+		})
+	}
+}
+
+// testTimeZones checks that stored times are instants, whatever the zone
+// of the process, the database session or the times given.
+func testTimeZones(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	id, _, err := db.PostThread(ctx, &models.Message{BoardID: 1, Title: "op"}, nil)
+	require.NoError(t, err)
+
+	message, err := db.GetMessage(ctx, 1, id)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), message.Created, time.Minute)
+
+	east := time.Now().Add(time.Hour).In(time.FixedZone("east", 14*60*60))
+	west := time.Now().Add(-time.Hour).In(time.FixedZone("west", -12*60*60))
+
+	active := postBan(t, db, &models.Ban{Network: "192.0.2.0/24", Expires: &east})
+	postBan(t, db, &models.Ban{Network: "192.0.2.0/24", Expires: &west})
+
+	bans, err := db.GetActiveBans(ctx, "192.0.2.1", "", 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{active}, banIDs(bans))
+	require.WithinDuration(t, time.Now(), bans[0].Created, time.Minute)
+	require.WithinDuration(t, east, *bans[0].Expires, time.Second)
+}
+
+func testConcurrentInserts(t *testing.T, newBackend NewBackend) {
+	ctx := context.Background()
+	db := newBackend(t, general)
+
+	const threads = 20
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+
+		ids       = map[uint64]bool{}
+		threadIDs = map[uint64]bool{}
+	)
+
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			id, threadID, err := db.PostThread(ctx, &models.Message{BoardID: 1, Title: "thread"}, nil)
+			if err == nil {
+				var reply uint64
+
+				reply, err = db.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: threadID, Title: "reply"})
+
+				mu.Lock()
+				ids[reply] = true
+				mu.Unlock()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs = append(errs, err)
+			ids[id] = true
+			threadIDs[threadID] = true
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.Len(t, ids, 2*threads, "message IDs are unique")
+	require.Len(t, threadIDs, threads, "thread IDs are unique")
+
+	board, err := db.GetBoard(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, board.Threads, 2*threads)
+
+	for threadID := range threadIDs {
+		thread, err := db.GetThread(ctx, 1, threadID)
+		require.NoError(t, err)
+		require.Len(t, thread, 2)
+	}
+}
+
+type thread struct {
+	id, threadID uint64
+}
+
+func postThread(t *testing.T, db database.Databaser, boardID uint64, title string) thread {
+	t.Helper()
+
+	id, threadID, err := db.PostThread(context.Background(), &models.Message{BoardID: boardID, Title: title}, nil)
+	require.NoError(t, err)
+
+	return thread{id: id, threadID: threadID}
+}
+
+func postReport(t *testing.T, db database.Databaser, boardID, messageID uint64) uint64 {
+	t.Helper()
+
+	id, err := db.PostReport(context.Background(), &models.Report{BoardID: boardID, MessageID: messageID, Reason: models.ReportReasonOther})
+	require.NoError(t, err)
+
+	return id
+}
+
+func postBan(t *testing.T, db database.Databaser, ban *models.Ban) uint64 {
+	t.Helper()
+
+	id, err := db.PostBan(context.Background(), ban)
+	require.NoError(t, err)
+
+	return id
+}
+
+func messageIDs(messages models.MessageList) []uint64 {
+	ids := []uint64{}
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+	}
+
+	return ids
+}
+
+func reportIDs(reports models.ReportList) []uint64 {
+	ids := []uint64{}
+	for _, r := range reports {
+		ids = append(ids, r.ID)
+	}
+
+	return ids
+}
+
+func banIDs(bans models.BanList) []uint64 {
+	ids := []uint64{}
+	for _, b := range bans {
+		ids = append(ids, b.ID)
+	}
+
+	return ids
+}
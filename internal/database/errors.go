@@ -0,0 +1,10 @@
+package database
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by conditional updates when the row is no
+	// longer in the state the update expects.
+	ErrConflict = errors.New("conflict")
+)
@@ -2,16 +2,38 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/Batyachelly/goBoard/internal/database/models"
 )
 
+// PosterIDFunc makes the poster ID of the opening post of a new thread,
+// which depends on the thread ID assigned on insert.
+type PosterIDFunc func(threadID uint64) (string, error)
+
 //go:generate mockery --name=Databaser --output=./../../generated/mocks
 type Databaser interface {
 	Migrate() error
 	GetBoardList(ctx context.Context) (models.BoardList, error)
 	GetBoard(ctx context.Context, boardID uint64) (*models.Board, error)
+	GetBoardSettings(ctx context.Context, boardID uint64) (*models.BoardSettings, error)
 	GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error)
-	PostThread(ctx context.Context, thread *models.Message) (uint64, uint64, error)
+	GetMessage(ctx context.Context, boardID, messageID uint64) (*models.Message, error)
+	PostThread(ctx context.Context, thread *models.Message, posterID PosterIDFunc) (uint64, uint64, error)
 	PostMessage(ctx context.Context, message *models.Message) (uint64, error)
+	BumpThread(ctx context.Context, boardID, threadID uint64) error
+	DeleteMessage(ctx context.Context, boardID, messageID uint64) error
+	DeleteMessageContent(ctx context.Context, boardID, messageID uint64) error
+	PurgeMessageIPs(ctx context.Context, before time.Time) (int64, error)
+
+	PostReport(ctx context.Context, report *models.Report) (uint64, error)
+	GetReport(ctx context.Context, reportID uint64) (*models.Report, error)
+	GetReportList(ctx context.Context, status int) (models.ReportList, error)
+	ResolveReport(ctx context.Context, reportID uint64, status int) error
+	ActOnReport(ctx context.Context, report *models.Report) error
+
+	PostBan(ctx context.Context, ban *models.Ban) (uint64, error)
+	GetBanList(ctx context.Context) (models.BanList, error)
+	GetActiveBans(ctx context.Context, ip, ipHash string, boardID uint64) (models.BanList, error)
+	DeleteBan(ctx context.Context, banID uint64) error
 }
@@ -0,0 +1,616 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+)
+
+// ErrConstraint is returned for writes the pg schema would reject, such as
+// a message on a board that does not exist.
+var ErrConstraint = errors.New("constraint violated")
+
+// DatabaseService keeps every row in process memory. IDs are the 1-based
+// positions in the tables and rows are never removed, only soft-deleted,
+// just like in pg.
+type DatabaseService struct {
+	mu sync.RWMutex
+
+	boards   []board
+	messages []message
+	reports  []models.Report
+	bans     []ban
+
+	// threadSeq mirrors the serial thread_id column, which only advances
+	// for new threads.
+	threadSeq uint64
+}
+
+type Config struct {
+	// Boards are the titles of the boards created with default settings.
+	Boards []string
+}
+
+type board struct {
+	models.Board
+	status   int
+	settings models.BoardSettings
+}
+
+type message struct {
+	models.Message
+	status int
+	bumped time.Time
+}
+
+type ban struct {
+	models.Ban
+	status int
+}
+
+func NewDatabaseService(cfg Config) *DatabaseService {
+	ds := new(DatabaseService)
+
+	for _, title := range cfg.Boards {
+		ds.CreateBoard(title, models.BoardSettings{ShowSage: true})
+	}
+
+	return ds
+}
+
+// CreateBoard adds an active board. Boards have no API, in pg they are
+// created by the administrator directly in the database.
+func (ds *DatabaseService) CreateBoard(title string, settings models.BoardSettings) uint64 {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	id := uint64(len(ds.boards) + 1)
+
+	ds.boards = append(ds.boards, board{
+		Board:    models.Board{ID: id, Title: title, Updated: time.Now().UTC()},
+		status:   models.Active,
+		settings: settings,
+	})
+
+	return id
+}
+
+// DeleteBoard soft-deletes a board, hiding it and its threads.
+func (ds *DatabaseService) DeleteBoard(boardID uint64) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.boardExists(boardID) {
+		ds.boards[boardID-1].status = models.Deleted
+	}
+}
+
+func (ds *DatabaseService) Migrate() error {
+	return nil
+}
+
+func (ds *DatabaseService) Close() {}
+
+func (ds *DatabaseService) Ping(ctx context.Context) error {
+	return nil
+}
+
+// CheckWritable never fails, memory always takes writes.
+func (ds *DatabaseService) CheckWritable(ctx context.Context) error {
+	return nil
+}
+
+func (ds *DatabaseService) GetBoardList(ctx context.Context) (models.BoardList, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	boards := models.BoardList{}
+
+	for _, b := range ds.boards {
+		if b.status > 0 {
+			boards = append(boards, models.Board{ID: b.ID, Title: b.Title})
+		}
+	}
+
+	return boards, nil
+}
+
+func (ds *DatabaseService) GetBoard(ctx context.Context, boardID uint64) (*models.Board, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	b, ok := ds.activeBoard(boardID)
+	if !ok {
+		return nil, fmt.Errorf("memory select board: %w", database.ErrNotFound)
+	}
+
+	var threads []message
+
+	for _, m := range ds.messages {
+		if m.status > 0 && m.BoardID == boardID {
+			threads = append(threads, m)
+		}
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].bumped.After(threads[j].bumped)
+	})
+
+	result := &models.Board{ID: b.ID, Title: b.Title, Updated: b.Updated}
+
+	for _, m := range threads {
+		result.Threads = append(result.Threads, listed(m))
+	}
+
+	return result, nil
+}
+
+func (ds *DatabaseService) GetBoardSettings(ctx context.Context, boardID uint64) (*models.BoardSettings, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	b, ok := ds.activeBoard(boardID)
+	if !ok {
+		return nil, fmt.Errorf("memory select board settings: %w", database.ErrNotFound)
+	}
+
+	settings := b.settings
+
+	return &settings, nil
+}
+
+func (ds *DatabaseService) GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	messages := models.MessageList{}
+
+	for _, m := range ds.messages {
+		if m.status > 0 && m.BoardID == boardID && m.ThreadID == threadID {
+			messages = append(messages, listed(m))
+		}
+	}
+
+	return messages, nil
+}
+
+func (ds *DatabaseService) PostThread(ctx context.Context, thread *models.Message, posterID database.PosterIDFunc) (uint64, uint64, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if !ds.boardExists(thread.BoardID) {
+		return 0, 0, fmt.Errorf("memory insert thread: board %d: %w", thread.BoardID, ErrConstraint)
+	}
+
+	// Like pg, a new thread gets no sage and only the poster ID made for it.
+	m := stored(*thread)
+	m.ThreadID = ds.threadSeq + 1
+	m.PosterID = ""
+	m.Sage = false
+
+	if posterID != nil {
+		pid, err := posterID(m.ThreadID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("memory make thread poster id: %w", err)
+		}
+
+		m.PosterID = pid
+	}
+
+	ds.threadSeq++
+
+	id := ds.insertMessage(m)
+	ds.touch(m.BoardID, id)
+
+	return id, m.ThreadID, nil
+}
+
+func (ds *DatabaseService) PostMessage(ctx context.Context, msg *models.Message) (uint64, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if !ds.boardExists(msg.BoardID) {
+		return 0, fmt.Errorf("memory insert message: board %d: %w", msg.BoardID, ErrConstraint)
+	}
+
+	id := ds.insertMessage(stored(*msg))
+	ds.touch(msg.BoardID, id)
+
+	return id, nil
+}
+
+func (ds *DatabaseService) BumpThread(ctx context.Context, boardID, threadID uint64) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	for i := range ds.messages {
+		if m := &ds.messages[i]; m.BoardID == boardID && m.ThreadID == threadID {
+			m.bumped = now
+		}
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) GetMessage(ctx context.Context, boardID, messageID uint64) (*models.Message, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	m := ds.message(messageID)
+	if m == nil || m.status <= 0 || m.BoardID != boardID {
+		return nil, fmt.Errorf("memory select message: %w", database.ErrNotFound)
+	}
+
+	return &models.Message{
+		ID:           m.ID,
+		BoardID:      m.BoardID,
+		ThreadID:     m.ThreadID,
+		Title:        m.Title,
+		Text:         m.Text,
+		Content:      m.Content,
+		Created:      m.Created,
+		PosterID:     m.PosterID,
+		IPHash:       m.IPHash,
+		IPEncrypted:  cloneBytes(m.IPEncrypted),
+		DeletionHash: m.DeletionHash,
+	}, nil
+}
+
+func (ds *DatabaseService) DeleteMessage(ctx context.Context, boardID, messageID uint64) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if m := ds.message(messageID); m != nil && m.BoardID == boardID {
+		m.status = models.Deleted
+	}
+
+	ds.touch(boardID, messageID)
+
+	return nil
+}
+
+func (ds *DatabaseService) DeleteMessageContent(ctx context.Context, boardID, messageID uint64) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if m := ds.message(messageID); m != nil && m.BoardID == boardID {
+		m.Content = ""
+	}
+
+	ds.touch(boardID, messageID)
+
+	return nil
+}
+
+func (ds *DatabaseService) PurgeMessageIPs(ctx context.Context, before time.Time) (int64, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	var purged int64
+
+	for i := range ds.messages {
+		if m := &ds.messages[i]; m.IPEncrypted != nil && m.Created.Before(before) {
+			m.IPEncrypted = nil
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (ds *DatabaseService) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if !ds.boardExists(report.BoardID) || ds.message(report.MessageID) == nil {
+		return 0, fmt.Errorf("memory insert report: message %d on board %d: %w", report.MessageID, report.BoardID, ErrConstraint)
+	}
+
+	id := uint64(len(ds.reports) + 1)
+
+	ds.reports = append(ds.reports, models.Report{
+		ID:        id,
+		BoardID:   report.BoardID,
+		MessageID: report.MessageID,
+		Reason:    report.Reason,
+		Comment:   report.Comment,
+		Status:    models.ReportOpen,
+		Created:   time.Now().UTC(),
+	})
+
+	return id, nil
+}
+
+func (ds *DatabaseService) GetReport(ctx context.Context, reportID uint64) (*models.Report, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	if reportID == 0 || reportID > uint64(len(ds.reports)) {
+		return nil, fmt.Errorf("memory select report: %w", database.ErrNotFound)
+	}
+
+	r := cloneReport(ds.reports[reportID-1])
+
+	return &r, nil
+}
+
+func (ds *DatabaseService) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	reports := models.ReportList{}
+
+	for _, r := range ds.reports {
+		if r.Status == status {
+			reports = append(reports, cloneReport(r))
+		}
+	}
+
+	return reports, nil
+}
+
+func (ds *DatabaseService) ResolveReport(ctx context.Context, reportID uint64, status int) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	return ds.resolveReport(reportID, status)
+}
+
+func (ds *DatabaseService) ActOnReport(ctx context.Context, report *models.Report) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if err := ds.resolveReport(report.ID, models.ReportActioned); err != nil {
+		return err
+	}
+
+	if m := ds.message(report.MessageID); m != nil && m.BoardID == report.BoardID {
+		m.status = models.Deleted
+	}
+
+	ds.touch(report.BoardID, report.MessageID)
+
+	for i := range ds.reports {
+		if r := &ds.reports[i]; r.MessageID == report.MessageID && r.Status == models.ReportOpen {
+			resolve(r, models.ReportActioned)
+		}
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) resolveReport(reportID uint64, status int) error {
+	if reportID == 0 || reportID > uint64(len(ds.reports)) {
+		return fmt.Errorf("memory resolve report: %w", database.ErrNotFound)
+	}
+
+	r := &ds.reports[reportID-1]
+	if r.Status != models.ReportOpen {
+		return fmt.Errorf("memory resolve report %d: %w", reportID, database.ErrConflict)
+	}
+
+	resolve(r, status)
+
+	return nil
+}
+
+func (ds *DatabaseService) PostBan(ctx context.Context, b *models.Ban) (uint64, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if b.Network == "" && b.IPHash == "" {
+		return 0, fmt.Errorf("memory insert ban: no network or address hash: %w", ErrConstraint)
+	}
+
+	if b.BoardID != 0 && !ds.boardExists(b.BoardID) {
+		return 0, fmt.Errorf("memory insert ban: board %d: %w", b.BoardID, ErrConstraint)
+	}
+
+	if b.Network != "" {
+		if _, _, err := net.ParseCIDR(b.Network); err != nil {
+			return 0, fmt.Errorf("memory insert ban: %w", err)
+		}
+	}
+
+	id := uint64(len(ds.bans) + 1)
+
+	ds.bans = append(ds.bans, ban{
+		Ban: models.Ban{
+			ID:        id,
+			BoardID:   b.BoardID,
+			Network:   b.Network,
+			IPHash:    b.IPHash,
+			Reason:    b.Reason,
+			Moderator: b.Moderator,
+			Created:   time.Now().UTC(),
+			Expires:   cloneTime(b.Expires),
+		},
+		status: models.Active,
+	})
+
+	return id, nil
+}
+
+func (ds *DatabaseService) GetBanList(ctx context.Context) (models.BanList, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	return ds.activeBans(func(b ban) bool { return true }), nil
+}
+
+func (ds *DatabaseService) GetActiveBans(ctx context.Context, ip, ipHash string, boardID uint64) (models.BanList, error) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+
+	addr := net.ParseIP(ip)
+
+	return ds.activeBans(func(b ban) bool {
+		if b.BoardID != 0 && b.BoardID != boardID {
+			return false
+		}
+
+		if b.IPHash != "" && b.IPHash == ipHash {
+			return true
+		}
+
+		_, network, err := net.ParseCIDR(b.Network)
+
+		return err == nil && addr != nil && network.Contains(addr)
+	}), nil
+}
+
+func (ds *DatabaseService) DeleteBan(ctx context.Context, banID uint64) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if banID == 0 || banID > uint64(len(ds.bans)) || ds.bans[banID-1].status <= 0 {
+		return fmt.Errorf("memory delete ban: %w", database.ErrNotFound)
+	}
+
+	ds.bans[banID-1].status = models.Deleted
+
+	return nil
+}
+
+func (ds *DatabaseService) activeBoard(boardID uint64) (board, bool) {
+	if !ds.boardExists(boardID) {
+		return board{}, false
+	}
+
+	b := ds.boards[boardID-1]
+
+	return b, b.status > 0
+}
+
+// boardExists reports whether the board row exists whatever its status,
+// as the pg foreign keys do.
+func (ds *DatabaseService) boardExists(boardID uint64) bool {
+	return boardID > 0 && boardID <= uint64(len(ds.boards))
+}
+
+func (ds *DatabaseService) message(messageID uint64) *message {
+	if messageID == 0 || messageID > uint64(len(ds.messages)) {
+		return nil
+	}
+
+	return &ds.messages[messageID-1]
+}
+
+func (ds *DatabaseService) insertMessage(m message) uint64 {
+	m.ID = uint64(len(ds.messages) + 1)
+
+	ds.messages = append(ds.messages, m)
+
+	return m.ID
+}
+
+// touch marks the thread of the message and its board as changed, which
+// moves the Last-Modified of both.
+func (ds *DatabaseService) touch(boardID, messageID uint64) {
+	m := ds.message(messageID)
+	if m == nil || m.BoardID != boardID {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	for i := range ds.messages {
+		if t := &ds.messages[i]; t.BoardID == boardID && t.ThreadID == m.ThreadID {
+			t.Updated = now
+		}
+	}
+
+	ds.boards[boardID-1].Updated = now
+}
+
+func (ds *DatabaseService) activeBans(match func(b ban) bool) models.BanList {
+	now := time.Now()
+	bans := models.BanList{}
+
+	for _, b := range ds.bans {
+		if b.status > 0 && (b.Expires == nil || b.Expires.After(now)) && match(b) {
+			active := b.Ban
+			active.Expires = cloneTime(b.Expires)
+			bans = append(bans, active)
+		}
+	}
+
+	return bans
+}
+
+// stored copies the columns pg stores for a new message.
+func stored(m models.Message) message {
+	now := time.Now().UTC()
+
+	return message{
+		Message: models.Message{
+			BoardID:      m.BoardID,
+			ThreadID:     m.ThreadID,
+			Title:        m.Title,
+			Text:         m.Text,
+			Content:      m.Content,
+			Created:      now,
+			PosterID:     m.PosterID,
+			Sage:         m.Sage,
+			IPHash:       m.IPHash,
+			IPEncrypted:  cloneBytes(m.IPEncrypted),
+			DeletionHash: m.DeletionHash,
+		},
+		status: models.Active,
+		bumped: now,
+	}
+}
+
+// listed returns the columns pg selects for board and thread listings.
+func listed(m message) models.Message {
+	return models.Message{
+		ID:       m.ID,
+		Title:    m.Title,
+		Text:     m.Text,
+		Content:  m.Content,
+		Created:  m.Created,
+		PosterID: m.PosterID,
+		Sage:     m.Sage,
+		Updated:  m.Updated,
+	}
+}
+
+func resolve(r *models.Report, status int) {
+	now := time.Now().UTC()
+
+	r.Status = status
+	r.Resolved = &now
+}
+
+func cloneReport(r models.Report) models.Report {
+	r.Resolved = cloneTime(r.Resolved)
+
+	return r
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+
+	return &c
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
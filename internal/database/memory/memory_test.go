@@ -0,0 +1,110 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/databasetest"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	databasetest.Run(t, func(t *testing.T, boards ...databasetest.Board) database.Databaser {
+		t.Helper()
+
+		ds := memory.NewDatabaseService(memory.Config{})
+
+		for _, b := range boards {
+			id := ds.CreateBoard(b.Title, b.Settings)
+			if b.Deleted {
+				ds.DeleteBoard(id)
+			}
+		}
+
+		return ds
+	})
+}
+
+func TestNewDatabaseService(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General", "Random"}})
+
+	boards, err := ds.GetBoardList(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, models.BoardList{{ID: 1, Title: "General"}, {ID: 2, Title: "Random"}}, boards)
+
+	settings, err := ds.GetBoardSettings(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, &models.BoardSettings{ShowSage: true}, settings, "boards start with the pg column defaults")
+}
+
+func TestDatabaseService_Constraints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	id, _, err := ds.PostThread(ctx, &models.Message{BoardID: 1}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "thread on missing board",
+			call: func() error {
+				_, _, err := ds.PostThread(ctx, &models.Message{BoardID: 2}, nil)
+
+				return err
+			},
+		},
+		{
+			name: "message on missing board",
+			call: func() error {
+				_, err := ds.PostMessage(ctx, &models.Message{BoardID: 2, ThreadID: 1})
+
+				return err
+			},
+		},
+		{
+			name: "report of missing message",
+			call: func() error {
+				_, err := ds.PostReport(ctx, &models.Report{BoardID: 1, MessageID: id + 1})
+
+				return err
+			},
+		},
+		{
+			name: "ban without target",
+			call: func() error {
+				_, err := ds.PostBan(ctx, &models.Ban{BoardID: 1})
+
+				return err
+			},
+		},
+		{
+			name: "ban on missing board",
+			call: func() error {
+				_, err := ds.PostBan(ctx, &models.Ban{BoardID: 2, IPHash: "hash"})
+
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorIs(t, tt.call(), memory.ErrConstraint)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrMigrationsPending = errors.New("migrations pending")
+	ErrBadVersion        = errors.New("bad migration version")
+	ErrIrreversible      = errors.New("migration has no down step")
+)
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// Migrator is implemented by the backends with a versioned schema.
+type Migrator interface {
+	// MigrateTo migrates up or down to the given version, 0 reverting every migration.
+	MigrateTo(ctx context.Context, version int32) error
+	MigrationStatus(ctx context.Context) (*MigrationStatus, error)
+	// MigrationPlan lists the steps MigrateTo would run to reach the version,
+	// without running them.
+	MigrationPlan(ctx context.Context, version int32) ([]MigrationStep, error)
+	// CheckMigrations fails unless the schema is at the latest known migration.
+	CheckMigrations(ctx context.Context) error
+}
+
+type Migration struct {
+	Version int32
+	Name    string
+	Applied bool
+}
+
+type MigrationStatus struct {
+	Current    int32
+	Latest     int32
+	Migrations []Migration
+}
+
+// MigrationStep is one migration run in one direction with the SQL it executes.
+type MigrationStep struct {
+	Version   int32
+	Name      string
+	Direction string
+	SQL       string
+}
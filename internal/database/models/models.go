@@ -7,13 +7,47 @@ const (
 	Active
 )
 
+const (
+	ReportOpen = iota
+	ReportDismissed
+	ReportActioned
+)
+
+const (
+	ReportReasonIllegal  = "illegal"
+	ReportReasonSpam     = "spam"
+	ReportReasonRules    = "rules"
+	ReportReasonOffTopic = "offtopic"
+	ReportReasonOther    = "other"
+)
+
 type Board struct {
 	ID    uint64 `json:"id"`
 	Title string `json:"title"`
+	// Updated is the last change to the board threads.
+	Updated time.Time `json:"-"`
 
 	Threads MessageList `json:"threads,omitempty"`
 }
 
+// BoardSettings are per-board options set by the board administrator.
+type BoardSettings struct {
+	// PosterIDs enables per-thread anonymous poster IDs.
+	PosterIDs bool `json:"posterIds"`
+	// ShowSage shows which replies were posted with sage.
+	ShowSage bool `json:"showSage"`
+}
+
+// ThreadFilter narrows the messages returned for a thread, zero fields match everything.
+type ThreadFilter struct {
+	PosterID string
+	// Since leaves out the messages up to this ID.
+	Since uint64
+
+	// Wait is how long to wait for a matching message when there is none yet.
+	Wait time.Duration
+}
+
 type Message struct {
 	ID       uint64    `json:"id"`
 	BoardID  uint64    `json:"-"`
@@ -22,8 +56,64 @@ type Message struct {
 	Text     string    `json:"text"`
 	Content  string    `json:"content"`
 	Created  time.Time `json:"created"`
+	PosterID string    `json:"posterId,omitempty"`
+	// Sage replies do not bump their thread.
+	Sage bool `json:"sage,omitempty"`
+	// Updated is the last change to the message thread, deletions included.
+	Updated time.Time `json:"-"`
+
+	// IP is the raw client address, it is never stored.
+	IP          string `json:"-"`
+	IPHash      string `json:"-"`
+	IPEncrypted []byte `json:"-"`
+
+	// DeletionPassword is the raw author password, it is never stored.
+	DeletionPassword string `json:"-"`
+	DeletionHash     string `json:"-"`
+}
+
+type Report struct {
+	ID        uint64     `json:"id"`
+	BoardID   uint64     `json:"boardId"`
+	MessageID uint64     `json:"messageId"`
+	Reason    string     `json:"reason"`
+	Comment   string     `json:"comment"`
+	Status    int        `json:"status"`
+	Created   time.Time  `json:"created"`
+	Resolved  *time.Time `json:"resolved,omitempty"`
+}
+
+type ReportGroup struct {
+	BoardID   uint64     `json:"boardId"`
+	MessageID uint64     `json:"messageId"`
+	Reports   ReportList `json:"reports"`
+}
+
+type MessageAuthor struct {
+	IP     string `json:"ip,omitempty"`
+	IPHash string `json:"ipHash"`
+}
+
+// Ban blocks posting from a network or, once the address itself has been
+// purged, from an address hash. A zero BoardID makes the ban global,
+// a nil Expires makes it permanent.
+type Ban struct {
+	ID        uint64     `json:"id"`
+	BoardID   uint64     `json:"boardId"`
+	Network   string     `json:"network,omitempty"`
+	IPHash    string     `json:"ipHash,omitempty"`
+	Reason    string     `json:"reason"`
+	Moderator string     `json:"moderator"`
+	Created   time.Time  `json:"created"`
+	Expires   *time.Time `json:"expires,omitempty"`
 }
 
 type BoardList []Board
 
 type MessageList []Message
+
+type ReportList []Report
+
+type ReportGroupList []ReportGroup
+
+type BanList []Ban
@@ -0,0 +1,219 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/tern/migrate"
+)
+
+func (ds *DatabaseService) Migrate() error {
+	ctx := context.Background()
+
+	return ds.withMigrator(ctx, func(m *migrate.Migrator) error {
+		if err := m.Migrate(ctx); err != nil {
+			return fmt.Errorf("pg try to migrate: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// MigrateTo migrates up or down to the given version, 0 reverting every migration.
+func (ds *DatabaseService) MigrateTo(ctx context.Context, version int32) error {
+	return ds.withMigrator(ctx, func(m *migrate.Migrator) error {
+		if version < 0 || version > int32(len(m.Migrations)) {
+			return fmt.Errorf("pg migrate to %d of %d: %w", version, len(m.Migrations), database.ErrBadVersion)
+		}
+
+		if err := m.MigrateTo(ctx, version); err != nil {
+			return fmt.Errorf("pg migrate to %d: %w", version, err)
+		}
+
+		return nil
+	})
+}
+
+func (ds *DatabaseService) MigrationStatus(ctx context.Context) (*database.MigrationStatus, error) {
+	status := new(database.MigrationStatus)
+
+	err := ds.withMigrator(ctx, func(m *migrate.Migrator) error {
+		current, err := m.GetCurrentVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("pg get schema version: %w", err)
+		}
+
+		status.Current = current
+		status.Latest = int32(len(m.Migrations))
+
+		for _, migration := range m.Migrations {
+			status.Migrations = append(status.Migrations, database.Migration{
+				Version: migration.Sequence,
+				Name:    migration.Name,
+				Applied: migration.Sequence <= current,
+			})
+		}
+
+		return nil
+	})
+
+	return status, err
+}
+
+// MigrationPlan lists the steps MigrateTo would run to reach the version,
+// without running them.
+func (ds *DatabaseService) MigrationPlan(ctx context.Context, version int32) ([]database.MigrationStep, error) {
+	var steps []database.MigrationStep
+
+	err := ds.withMigrator(ctx, func(m *migrate.Migrator) error {
+		current, err := m.GetCurrentVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("pg get schema version: %w", err)
+		}
+
+		latest := int32(len(m.Migrations))
+		if version < 0 || version > latest || current > latest {
+			return fmt.Errorf("pg plan from %d to %d of %d: %w", current, version, latest, database.ErrBadVersion)
+		}
+
+		for v := current; v < version; v++ {
+			migration := m.Migrations[v]
+			steps = append(steps, database.MigrationStep{
+				Version:   migration.Sequence,
+				Name:      migration.Name,
+				Direction: database.DirectionUp,
+				SQL:       migration.UpSQL,
+			})
+		}
+
+		for v := current; v > version; v-- {
+			migration := m.Migrations[v-1]
+			if migration.DownSQL == "" {
+				return fmt.Errorf("pg plan %s: %w", migration.Name, database.ErrIrreversible)
+			}
+
+			steps = append(steps, database.MigrationStep{
+				Version:   migration.Sequence,
+				Name:      migration.Name,
+				Direction: database.DirectionDown,
+				SQL:       migration.DownSQL,
+			})
+		}
+
+		return nil
+	})
+
+	return steps, err
+}
+
+// CheckMigrations fails unless the schema is at the latest known migration.
+// It only reads the version, the migrations are counted at startup.
+func (ds *DatabaseService) CheckMigrations(ctx context.Context) error {
+	var current int32
+
+	if err := ds.pool.QueryRow(ctx, "select version from "+ds.versionTable).Scan(&current); err != nil {
+		return fmt.Errorf("pg get schema version: %w", err)
+	}
+
+	if current != ds.latest {
+		return fmt.Errorf("pg schema version %d, expected %d: %w", current, ds.latest, database.ErrMigrationsPending)
+	}
+
+	return nil
+}
+
+// migrationName is the tern migration file name, the number being the version.
+var migrationName = regexp.MustCompile(`^(\d+)_.+\.sql$`)
+
+// latestMigration is the version of the last of the migrations, which
+// tern requires to be numbered from 1 without gaps.
+func latestMigration(fsys fs.FS) (int32, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return 0, fmt.Errorf("pg find migrations: %w", err)
+	}
+
+	var latest int32
+
+	for _, path := range paths {
+		if migrationName.MatchString(path) {
+			latest++
+		}
+	}
+
+	return latest, nil
+}
+
+func (ds *DatabaseService) withMigrator(ctx context.Context, f func(m *migrate.Migrator) error) error {
+	conn, err := ds.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("pg acquire connection for migration: %w", err)
+	}
+
+	defer conn.Release()
+
+	m, err := ds.migrator(ctx, conn.Conn())
+	if err != nil {
+		return err
+	}
+
+	return f(m)
+}
+
+func (ds *DatabaseService) migrator(ctx context.Context, conn *pgx.Conn) (*migrate.Migrator, error) {
+	m, err := migrate.NewMigratorEx(ctx, conn, ds.versionTable, &migrate.MigratorOptions{
+		MigratorFS: MigratorFS(ds.migrations),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pg create migrator: %w", err)
+	}
+
+	if err := m.LoadMigrations("."); err != nil {
+		return nil, fmt.Errorf("pg load migrations: %w", err)
+	}
+
+	return m, nil
+}
+
+// MigratorFS adapts an fs.FS, such as the embedded migrations, to tern.
+func MigratorFS(fsys fs.FS) migrate.MigratorFS {
+	return migratorFS{fsys: fsys}
+}
+
+type migratorFS struct {
+	fsys fs.FS
+}
+
+func (m migratorFS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	entries, err := fs.ReadDir(m.fsys, dirname)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	infos := make([]os.FileInfo, 0, len(entries))
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (m migratorFS) ReadFile(filename string) ([]byte, error) {
+	return fs.ReadFile(m.fsys, filename) //nolint:wrapcheck
+}
+
+func (m migratorFS) Glob(pattern string) ([]string, error) {
+	return fs.Glob(m.fsys, pattern) //nolint:wrapcheck
+}
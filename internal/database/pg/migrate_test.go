@@ -0,0 +1,36 @@
+package pg_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/database/pg"
+	"github.com/Batyachelly/goBoard/migration"
+
+	"github.com/jackc/tern/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigratorFS_EmbeddedMigrations(t *testing.T) {
+	t.Parallel()
+
+	fsys := pg.MigratorFS(migration.FS)
+
+	paths, err := migrate.FindMigrationsEx(".", fsys)
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	onDisk, err := filepath.Glob(filepath.Join("..", "..", "..", "migration", "*.sql"))
+	require.NoError(t, err)
+	require.Len(t, paths, len(onDisk))
+
+	for _, path := range paths {
+		body, err := fsys.ReadFile(path)
+		require.NoError(t, err)
+
+		parts := strings.SplitN(string(body), "---- create above / drop below ----", 2)
+		require.Len(t, parts, 2, "%s has no down migration", path)
+		require.NotEmpty(t, strings.TrimSpace(parts[1]), "%s has an empty down migration", path)
+	}
+}
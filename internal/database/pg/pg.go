@@ -2,17 +2,28 @@ package pg
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"time"
 
+	"github.com/Batyachelly/goBoard/internal/database"
 	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/tracing"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/jackc/tern/migrate"
 )
 
 type DatabaseService struct {
 	pool         *pgxpool.Pool
+	db           querier
+	tracer       *tracing.Tracer
 	versionTable string
+	migrations   fs.FS
+	// latest is the version of the last migration, counted once for the
+	// readiness check.
+	latest int32
 }
 
 type Config struct {
@@ -23,6 +34,11 @@ type Config struct {
 	DB           string
 	SSLMode      string
 	VersionTable string
+
+	// Migrations holds the numbered tern migration files at its root.
+	Migrations fs.FS
+
+	Tracer *tracing.Tracer
 }
 
 func NewDatabaseService(cfg Config) (*DatabaseService, error) {
@@ -34,41 +50,75 @@ func NewDatabaseService(cfg Config) (*DatabaseService, error) {
 		return nil, fmt.Errorf("pg parse config: %w", err)
 	}
 
+	// Columns without a time zone hold UTC, which is how pgx reads them, so
+	// the defaults and now() must not follow the server time zone.
+	poolCfg.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
 	pool, err := pgxpool.ConnectConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("pg connect config: %w", err)
 	}
 
-	return &DatabaseService{
+	ds := &DatabaseService{
 		pool:         pool,
+		db:           tracedQuerier{q: pool, tracer: cfg.Tracer},
+		tracer:       cfg.Tracer,
 		versionTable: cfg.VersionTable,
-	}, nil
+		migrations:   cfg.Migrations,
+	}
+
+	if cfg.Migrations != nil {
+		if ds.latest, err = latestMigration(cfg.Migrations); err != nil {
+			pool.Close()
+
+			return nil, err
+		}
+	}
+
+	return ds, nil
+}
+
+func (ds *DatabaseService) traced(q querier) querier {
+	return tracedQuerier{q: q, tracer: ds.tracer}
 }
 
-func (ds *DatabaseService) Migrate() error {
-	ctx := context.Background()
+func (ds *DatabaseService) Close() {
+	ds.pool.Close()
+}
 
-	conn, err := ds.pool.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("pg acquire connection for migration: %w", err)
+// Stat returns a snapshot of the connection pool statistics.
+func (ds *DatabaseService) Stat() *pgxpool.Stat {
+	return ds.pool.Stat()
+}
+
+func (ds *DatabaseService) Ping(ctx context.Context) error {
+	if err := ds.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("pg ping: %w", err)
 	}
 
-	defer conn.Conn().Close(ctx)
+	return nil
+}
 
-	m, err := migrate.NewMigratorEx(ctx, conn.Conn(), ds.versionTable, nil)
+// CheckWritable fails when the database takes no writes, as a hot standby
+// or with default_transaction_read_only on. The probe write changes no rows
+// and is rolled back.
+func (ds *DatabaseService) CheckWritable(ctx context.Context) error {
+	tx, err := ds.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("pg create migrator: %w", err)
+		return fmt.Errorf("pg start write probe: %w", err)
 	}
 
-	if err := m.Migrate(ctx); err != nil {
-		return fmt.Errorf("pg try to migrate: %w", err)
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if _, err := tx.Exec(ctx, "update board set title=title where false"); err != nil {
+		return fmt.Errorf("pg write probe: %w", err)
 	}
 
 	return nil
 }
 
 func (ds *DatabaseService) GetBoardList(ctx context.Context) (models.BoardList, error) {
-	rows, err := ds.pool.Query(ctx, "select id, title from board where status>0")
+	rows, err := ds.db.Query(ctx, "select id, title from board where status>0 order by id")
 	if err != nil {
 		return nil, fmt.Errorf("pg select boards: %w", err)
 	}
@@ -102,15 +152,15 @@ func (ds *DatabaseService) GetBoard(ctx context.Context, boardID uint64) (*model
 	board.ID = boardID
 
 	{
-		row := ds.pool.QueryRow(ctx, "select id, title from board where status>0 and id=$1 limit 1", boardID)
+		row := ds.db.QueryRow(ctx, "select id, title, updated from board where status>0 and id=$1 limit 1", boardID)
 
-		if err := row.Scan(&board.ID, &board.Title); err != nil {
-			return nil, fmt.Errorf("pg select board: %w", err)
+		if err := row.Scan(&board.ID, &board.Title, &board.Updated); err != nil {
+			return nil, fmt.Errorf("pg select board: %w", notFound(err))
 		}
 	}
 
 	{
-		rows, err := tx.Query(ctx, "select id, title, text, content, created from message where status>0 and board_id=$1", boardID)
+		rows, err := ds.traced(tx).Query(ctx, "select id, title, text, content, created, coalesce(poster_id, ''), sage, updated from message where status>0 and board_id=$1 order by bumped desc, id", boardID)
 		if err != nil {
 			return nil, fmt.Errorf("pg select board threads: %w", err)
 		}
@@ -119,7 +169,7 @@ func (ds *DatabaseService) GetBoard(ctx context.Context, boardID uint64) (*model
 		for rows.Next() {
 			m := models.Message{}
 
-			if err := rows.Scan(&m.ID, &m.Title, &m.Text, &m.Content, &m.Created); err != nil {
+			if err := rows.Scan(&m.ID, &m.Title, &m.Text, &m.Content, &m.Created, &m.PosterID, &m.Sage, &m.Updated); err != nil {
 				return nil, fmt.Errorf("pg scan messages: %w", err)
 			}
 
@@ -134,8 +184,20 @@ func (ds *DatabaseService) GetBoard(ctx context.Context, boardID uint64) (*model
 	return board, nil
 }
 
+func (ds *DatabaseService) GetBoardSettings(ctx context.Context, boardID uint64) (*models.BoardSettings, error) {
+	row := ds.db.QueryRow(ctx, "select poster_ids, show_sage from board where status>0 and id=$1 limit 1", boardID)
+
+	settings := new(models.BoardSettings)
+
+	if err := row.Scan(&settings.PosterIDs, &settings.ShowSage); err != nil {
+		return nil, fmt.Errorf("pg select board settings: %w", notFound(err))
+	}
+
+	return settings, nil
+}
+
 func (ds *DatabaseService) GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error) {
-	rows, err := ds.pool.Query(ctx, "select id, title, text, content, created from message where status>0 and board_id=$1 and thread_id=$2", boardID, threadID)
+	rows, err := ds.db.Query(ctx, "select id, title, text, content, created, coalesce(poster_id, ''), sage, updated from message where status>0 and board_id=$1 and thread_id=$2 order by id", boardID, threadID)
 	if err != nil {
 		return nil, fmt.Errorf("pg select comments: %w", err)
 	}
@@ -147,7 +209,7 @@ func (ds *DatabaseService) GetThread(ctx context.Context, boardID, threadID uint
 	for rows.Next() {
 		m := models.Message{}
 
-		if err := rows.Scan(&m.ID, &m.Title, &m.Text, &m.Content, &m.Created); err != nil {
+		if err := rows.Scan(&m.ID, &m.Title, &m.Text, &m.Content, &m.Created, &m.PosterID, &m.Sage, &m.Updated); err != nil {
 			return nil, fmt.Errorf("pg scan message: %w", err)
 		}
 
@@ -157,9 +219,18 @@ func (ds *DatabaseService) GetThread(ctx context.Context, boardID, threadID uint
 	return messages, nil
 }
 
-func (ds *DatabaseService) PostThread(ctx context.Context, thread *models.Message) (uint64, uint64, error) {
-	row := ds.pool.QueryRow(ctx, "insert into message (status, board_id, title, text, content) values (1, $1, $2, $3, $4) returning id, thread_id",
-		thread.BoardID, thread.Title, thread.Text, thread.Content)
+func (ds *DatabaseService) PostThread(ctx context.Context, thread *models.Message, posterID database.PosterIDFunc) (uint64, uint64, error) {
+	tx, err := ds.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("pg start tx for post thread: %w", err)
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	q := ds.traced(tx)
+
+	row := q.QueryRow(ctx, "insert into message (status, board_id, title, text, content, ip_hash, ip_encrypted, deletion_hash) values (1, $1, $2, $3, $4, nullif($5, ''), $6, nullif($7, '')) returning id, thread_id",
+		thread.BoardID, thread.Title, thread.Text, thread.Content, thread.IPHash, thread.IPEncrypted, thread.DeletionHash)
 
 	var id, threadID uint64
 
@@ -167,12 +238,42 @@ func (ds *DatabaseService) PostThread(ctx context.Context, thread *models.Messag
 		return 0, 0, fmt.Errorf("pg insert thread: %w", err)
 	}
 
+	// The thread ID comes from the sequence on insert, so the poster ID is
+	// set right after it in the same transaction.
+	if posterID != nil {
+		pid, err := posterID(threadID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("pg make thread poster id: %w", err)
+		}
+
+		if _, err := q.Exec(ctx, "update message set poster_id=$1 where id=$2", pid, id); err != nil {
+			return 0, 0, fmt.Errorf("pg update thread poster id: %w", err)
+		}
+	}
+
+	if err := touch(ctx, q, thread.BoardID, id); err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("pg commit tx for post thread: %w", err)
+	}
+
 	return id, threadID, nil
 }
 
 func (ds *DatabaseService) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
-	row := ds.pool.QueryRow(ctx, "insert into message (status, board_id, thread_id, title, text, content) values (1, $1, $2, $3, $4, $5) returning id",
-		message.BoardID, message.ThreadID, message.Title, message.Text, message.Content)
+	tx, err := ds.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("pg start tx for post message: %w", err)
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	q := ds.traced(tx)
+
+	row := q.QueryRow(ctx, "insert into message (status, board_id, thread_id, title, text, content, ip_hash, ip_encrypted, poster_id, deletion_hash, sage) values (1, $1, $2, $3, $4, $5, nullif($6, ''), $7, nullif($8, ''), nullif($9, ''), $10) returning id",
+		message.BoardID, message.ThreadID, message.Title, message.Text, message.Content, message.IPHash, message.IPEncrypted, message.PosterID, message.DeletionHash, message.Sage)
 
 	var id uint64
 
@@ -180,5 +281,263 @@ func (ds *DatabaseService) PostMessage(ctx context.Context, message *models.Mess
 		return 0, fmt.Errorf("pg insert message: %w", err)
 	}
 
+	if err := touch(ctx, q, message.BoardID, id); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("pg commit tx for post message: %w", err)
+	}
+
 	return id, nil
 }
+
+func (ds *DatabaseService) BumpThread(ctx context.Context, boardID, threadID uint64) error {
+	if _, err := ds.db.Exec(ctx, "update message set bumped=now() where board_id=$1 and thread_id=$2", boardID, threadID); err != nil {
+		return fmt.Errorf("pg bump thread: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) GetMessage(ctx context.Context, boardID, messageID uint64) (*models.Message, error) {
+	row := ds.db.QueryRow(ctx, "select id, board_id, thread_id, title, text, content, created, coalesce(poster_id, ''), coalesce(ip_hash, ''), ip_encrypted, coalesce(deletion_hash, '') from message where status>0 and board_id=$1 and id=$2 limit 1", boardID, messageID)
+
+	m := new(models.Message)
+
+	if err := row.Scan(&m.ID, &m.BoardID, &m.ThreadID, &m.Title, &m.Text, &m.Content, &m.Created, &m.PosterID, &m.IPHash, &m.IPEncrypted, &m.DeletionHash); err != nil {
+		return nil, fmt.Errorf("pg select message: %w", notFound(err))
+	}
+
+	return m, nil
+}
+
+func (ds *DatabaseService) DeleteMessage(ctx context.Context, boardID, messageID uint64) error {
+	return ds.updateMessage(ctx, "delete message", boardID, messageID, "update message set status=$1 where board_id=$2 and id=$3", models.Deleted, boardID, messageID)
+}
+
+func (ds *DatabaseService) DeleteMessageContent(ctx context.Context, boardID, messageID uint64) error {
+	return ds.updateMessage(ctx, "delete message content", boardID, messageID, "update message set content='' where board_id=$1 and id=$2", boardID, messageID)
+}
+
+// updateMessage runs the update of a message together with the touch of
+// its thread and board.
+func (ds *DatabaseService) updateMessage(ctx context.Context, action string, boardID, messageID uint64, query string, args ...interface{}) error {
+	tx, err := ds.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("pg start tx for %s: %w", action, err)
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	q := ds.traced(tx)
+
+	if _, err := q.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("pg %s: %w", action, err)
+	}
+
+	if err := touch(ctx, q, boardID, messageID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("pg commit tx for %s: %w", action, err)
+	}
+
+	return nil
+}
+
+// touch marks the thread of the message and its board as changed, which
+// moves the Last-Modified of both.
+func touch(ctx context.Context, q querier, boardID, messageID uint64) error {
+	if _, err := q.Exec(ctx, "update message set updated=now() where board_id=$1 and thread_id=(select thread_id from message where board_id=$1 and id=$2)", boardID, messageID); err != nil {
+		return fmt.Errorf("pg touch thread: %w", err)
+	}
+
+	if _, err := q.Exec(ctx, "update board set updated=now() where id=$1", boardID); err != nil {
+		return fmt.Errorf("pg touch board: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) PurgeMessageIPs(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := ds.db.Exec(ctx, "update message set ip_encrypted=null where ip_encrypted is not null and created<$1", before)
+	if err != nil {
+		return 0, fmt.Errorf("pg purge message ips: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (ds *DatabaseService) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	row := ds.db.QueryRow(ctx, "insert into report (status, board_id, message_id, reason, comment) values ($1, $2, $3, $4, $5) returning id",
+		models.ReportOpen, report.BoardID, report.MessageID, report.Reason, report.Comment)
+
+	var id uint64
+
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("pg insert report: %w", err)
+	}
+
+	return id, nil
+}
+
+func (ds *DatabaseService) GetReport(ctx context.Context, reportID uint64) (*models.Report, error) {
+	row := ds.db.QueryRow(ctx, "select id, board_id, message_id, reason, comment, status, created, resolved from report where id=$1 limit 1", reportID)
+
+	r := new(models.Report)
+
+	if err := row.Scan(&r.ID, &r.BoardID, &r.MessageID, &r.Reason, &r.Comment, &r.Status, &r.Created, &r.Resolved); err != nil {
+		return nil, fmt.Errorf("pg select report: %w", notFound(err))
+	}
+
+	return r, nil
+}
+
+func (ds *DatabaseService) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	rows, err := ds.db.Query(ctx, "select id, board_id, message_id, reason, comment, status, created, resolved from report where status=$1 order by id", status)
+	if err != nil {
+		return nil, fmt.Errorf("pg select reports: %w", err)
+	}
+
+	defer rows.Close()
+
+	reports := models.ReportList{}
+
+	for rows.Next() {
+		r := models.Report{}
+
+		if err := rows.Scan(&r.ID, &r.BoardID, &r.MessageID, &r.Reason, &r.Comment, &r.Status, &r.Created, &r.Resolved); err != nil {
+			return nil, fmt.Errorf("pg scan report: %w", err)
+		}
+
+		reports = append(reports, r)
+	}
+
+	return reports, nil
+}
+
+func (ds *DatabaseService) ResolveReport(ctx context.Context, reportID uint64, status int) error {
+	tag, err := ds.db.Exec(ctx, "update report set status=$1, resolved=now() where id=$2 and status=$3", status, reportID, models.ReportOpen)
+	if err != nil {
+		return fmt.Errorf("pg resolve report: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pg resolve report %d: %w", reportID, database.ErrConflict)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) ActOnReport(ctx context.Context, report *models.Report) error {
+	tx, err := ds.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("pg start tx for act on report: %w", err)
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	q := ds.traced(tx)
+
+	tag, err := q.Exec(ctx, "update report set status=$1, resolved=now() where id=$2 and status=$3", models.ReportActioned, report.ID, models.ReportOpen)
+	if err != nil {
+		return fmt.Errorf("pg resolve report: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pg resolve report %d: %w", report.ID, database.ErrConflict)
+	}
+
+	if _, err := q.Exec(ctx, "update message set status=$1 where board_id=$2 and id=$3", models.Deleted, report.BoardID, report.MessageID); err != nil {
+		return fmt.Errorf("pg delete reported message: %w", err)
+	}
+
+	if err := touch(ctx, q, report.BoardID, report.MessageID); err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(ctx, "update report set status=$1, resolved=now() where message_id=$2 and status=$3", models.ReportActioned, report.MessageID, models.ReportOpen); err != nil {
+		return fmt.Errorf("pg resolve message reports: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("pg commit tx for act on report: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	row := ds.db.QueryRow(ctx, "insert into ban (status, board_id, network, ip_hash, reason, moderator, expires) values (1, nullif($1, 0), nullif($2, '')::cidr, nullif($3, ''), $4, $5, $6) returning id",
+		ban.BoardID, ban.Network, ban.IPHash, ban.Reason, ban.Moderator, ban.Expires)
+
+	var id uint64
+
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("pg insert ban: %w", err)
+	}
+
+	return id, nil
+}
+
+func (ds *DatabaseService) GetBanList(ctx context.Context) (models.BanList, error) {
+	rows, err := ds.db.Query(ctx, "select id, coalesce(board_id, 0), coalesce(network::text, ''), coalesce(ip_hash, ''), reason, moderator, created, expires from ban where status>0 and (expires is null or expires>now()) order by id")
+	if err != nil {
+		return nil, fmt.Errorf("pg select bans: %w", err)
+	}
+
+	defer rows.Close()
+
+	return scanBans(rows)
+}
+
+func (ds *DatabaseService) GetActiveBans(ctx context.Context, ip, ipHash string, boardID uint64) (models.BanList, error) {
+	rows, err := ds.db.Query(ctx, "select id, coalesce(board_id, 0), coalesce(network::text, ''), coalesce(ip_hash, ''), reason, moderator, created, expires from ban where status>0 and (expires is null or expires>now()) and (board_id is null or board_id=$3) and (network>>=nullif($1, '')::inet or ip_hash=$2) order by id",
+		ip, ipHash, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("pg select active bans: %w", err)
+	}
+
+	defer rows.Close()
+
+	return scanBans(rows)
+}
+
+func (ds *DatabaseService) DeleteBan(ctx context.Context, banID uint64) error {
+	tag, err := ds.db.Exec(ctx, "update ban set status=$1 where status>0 and id=$2", models.Deleted, banID)
+	if err != nil {
+		return fmt.Errorf("pg delete ban: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pg delete ban: %w", database.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanBans(rows pgx.Rows) (models.BanList, error) {
+	bans := models.BanList{}
+
+	for rows.Next() {
+		b := models.Ban{}
+
+		if err := rows.Scan(&b.ID, &b.BoardID, &b.Network, &b.IPHash, &b.Reason, &b.Moderator, &b.Created, &b.Expires); err != nil {
+			return nil, fmt.Errorf("pg scan ban: %w", err)
+		}
+
+		bans = append(bans, b)
+	}
+
+	return bans, nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return database.ErrNotFound
+	}
+
+	return err
+}
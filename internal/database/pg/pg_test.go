@@ -0,0 +1,81 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/config"
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/databasetest"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/database/pg"
+	"github.com/Batyachelly/goBoard/migration"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// testDBEnv names a throwaway database to run the conformance suite on,
+// connecting with the usual POSTGRES_* settings. Its tables are truncated
+// before every test.
+const testDBEnv = "POSTGRES_TEST_DB"
+
+func TestConformance(t *testing.T) {
+	name := os.Getenv(testDBEnv)
+	if name == "" {
+		t.Skipf("%s is not set", testDBEnv)
+	}
+
+	cfg, err := config.Load("", os.LookupEnv)
+	require.NoError(t, err)
+
+	cfg.Postgres.DB = name
+
+	ds, err := pg.NewDatabaseService(pg.Config{
+		Host:         cfg.Postgres.Host,
+		Port:         cfg.Postgres.Port,
+		User:         cfg.Postgres.User,
+		Password:     cfg.Postgres.Password,
+		DB:           cfg.Postgres.DB,
+		SSLMode:      cfg.Postgres.SSLMode,
+		VersionTable: cfg.Postgres.VersionTable,
+		Migrations:   migration.FS,
+	})
+	require.NoError(t, err)
+
+	defer ds.Close()
+
+	require.NoError(t, ds.Migrate())
+	require.NoError(t, ds.CheckMigrations(context.Background()))
+	require.NoError(t, ds.CheckWritable(context.Background()))
+
+	conn, err := pgx.Connect(context.Background(), fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.User, cfg.Postgres.Password, cfg.Postgres.DB, cfg.Postgres.SSLMode))
+	require.NoError(t, err)
+
+	defer conn.Close(context.Background())
+
+	databasetest.Run(t, func(t *testing.T, boards ...databasetest.Board) database.Databaser {
+		t.Helper()
+
+		ctx := context.Background()
+
+		_, err := conn.Exec(ctx, "truncate board, message, report, ban restart identity cascade")
+		require.NoError(t, err)
+
+		for _, b := range boards {
+			status := models.Active
+			if b.Deleted {
+				status = models.Deleted
+			}
+
+			_, err := conn.Exec(ctx, "insert into board (status, title, poster_ids, show_sage) values ($1, $2, $3, $4)",
+				status, b.Title, b.Settings.PosterIDs, b.Settings.ShowSage)
+			require.NoError(t, err)
+		}
+
+		return ds
+	})
+}
@@ -0,0 +1,113 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Batyachelly/goBoard/internal/tracing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// tracedQuerier starts a client span for every statement run through it.
+// Spans of Query and QueryRow end once the result has been read.
+type tracedQuerier struct {
+	q      querier
+	tracer *tracing.Tracer
+}
+
+func (t tracedQuerier) start(ctx context.Context, sql string) (context.Context, trace.Span) {
+	op := "query"
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		op = strings.ToLower(fields[0])
+	}
+
+	return t.tracer.Start(ctx, "pg."+op, trace.SpanKindClient,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBStatementKey.String(sql),
+	)
+}
+
+func (t tracedQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := t.start(ctx, sql)
+	defer span.End()
+
+	tag, err := t.q.Exec(ctx, sql, args...)
+	tracing.RecordError(span, err)
+	span.SetAttributes(attribute.Int64("db.rows_affected", tag.RowsAffected()))
+
+	return tag, err //nolint:wrapcheck
+}
+
+func (t tracedQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := t.start(ctx, sql)
+
+	rows, err := t.q.Query(ctx, sql, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		span.End()
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (t tracedQuerier) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, span := t.start(ctx, sql)
+
+	return tracedRow{row: t.q.QueryRow(ctx, sql, args...), span: span}
+}
+
+type tracedRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+
+	r.end()
+
+	return false
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	r.end()
+}
+
+func (r *tracedRows) end() {
+	tracing.RecordError(r.span, r.Rows.Err())
+	r.span.End()
+}
+
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		tracing.RecordError(r.span, err)
+	}
+
+	r.span.End()
+
+	return err //nolint:wrapcheck
+}
@@ -0,0 +1,240 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+)
+
+// The migration files follow the tern format used for Postgres: numbered
+// from 1 without gaps, the down step below the separator.
+const (
+	versionTable       = "schema_version"
+	migrationSeparator = "---- create above / drop below ----"
+)
+
+type migration struct {
+	version int32
+	name    string
+	up      string
+	down    string
+}
+
+func (ds *DatabaseService) Migrate() error {
+	ctx := context.Background()
+
+	if err := ds.migrateTo(ctx, int32(len(ds.migrations))); err != nil {
+		return fmt.Errorf("sqlite try to migrate: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateTo migrates up or down to the given version, 0 reverting every migration.
+func (ds *DatabaseService) MigrateTo(ctx context.Context, version int32) error {
+	if err := ds.migrateTo(ctx, version); err != nil {
+		return fmt.Errorf("sqlite migrate to %d: %w", version, err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) MigrationStatus(ctx context.Context) (*database.MigrationStatus, error) {
+	current, err := ds.currentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &database.MigrationStatus{
+		Current: current,
+		Latest:  int32(len(ds.migrations)),
+	}
+
+	for _, m := range ds.migrations {
+		status.Migrations = append(status.Migrations, database.Migration{
+			Version: m.version,
+			Name:    m.name,
+			Applied: m.version <= current,
+		})
+	}
+
+	return status, nil
+}
+
+// MigrationPlan lists the steps MigrateTo would run to reach the version,
+// without running them.
+func (ds *DatabaseService) MigrationPlan(ctx context.Context, version int32) ([]database.MigrationStep, error) {
+	current, err := ds.currentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan(ds.migrations, current, version)
+}
+
+// CheckMigrations fails unless the schema is at the latest known migration.
+func (ds *DatabaseService) CheckMigrations(ctx context.Context) error {
+	status, err := ds.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Current != status.Latest {
+		return fmt.Errorf("sqlite schema version %d, expected %d: %w", status.Current, status.Latest, database.ErrMigrationsPending)
+	}
+
+	return nil
+}
+
+// migrateTo runs every step in its own transaction together with the
+// version update, so a failed step leaves the previous version in place.
+func (ds *DatabaseService) migrateTo(ctx context.Context, version int32) error {
+	current, err := ds.currentVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	steps, err := plan(ds.migrations, current, version)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		next := step.Version
+		if step.Direction == database.DirectionDown {
+			next--
+		}
+
+		if err := ds.runStep(ctx, step, next); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) runStep(ctx context.Context, step database.MigrationStep, version int32) error {
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("start tx for %s %s: %w", step.Direction, step.Name, err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, step.SQL); err != nil {
+		return fmt.Errorf("%s %s: %w", step.Direction, step.Name, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "update "+versionTable+" set version=?", version); err != nil {
+		return fmt.Errorf("update schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit %s %s: %w", step.Direction, step.Name, err)
+	}
+
+	return nil
+}
+
+// currentVersion returns the schema version, creating the version table
+// at version 0 on first use.
+func (ds *DatabaseService) currentVersion(ctx context.Context) (int32, error) {
+	_, err := ds.db.ExecContext(ctx, "create table if not exists "+versionTable+" (version integer not null);"+
+		"insert into "+versionTable+" (version) select 0 where not exists (select 1 from "+versionTable+")")
+	if err != nil {
+		return 0, fmt.Errorf("sqlite create version table: %w", err)
+	}
+
+	var version int32
+
+	if err := ds.db.QueryRowContext(ctx, "select version from "+versionTable).Scan(&version); err != nil {
+		return 0, fmt.Errorf("sqlite get schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+func plan(migrations []migration, current, version int32) ([]database.MigrationStep, error) {
+	latest := int32(len(migrations))
+	if version < 0 || version > latest || current > latest {
+		return nil, fmt.Errorf("sqlite plan from %d to %d of %d: %w", current, version, latest, database.ErrBadVersion)
+	}
+
+	var steps []database.MigrationStep
+
+	for v := current; v < version; v++ {
+		m := migrations[v]
+		steps = append(steps, database.MigrationStep{
+			Version:   m.version,
+			Name:      m.name,
+			Direction: database.DirectionUp,
+			SQL:       m.up,
+		})
+	}
+
+	for v := current; v > version; v-- {
+		m := migrations[v-1]
+		if m.down == "" {
+			return nil, fmt.Errorf("sqlite plan %s: %w", m.name, database.ErrIrreversible)
+		}
+
+		steps = append(steps, database.MigrationStep{
+			Version:   m.version,
+			Name:      m.name,
+			Direction: database.DirectionDown,
+			SQL:       m.down,
+		})
+	}
+
+	return steps, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite find migrations: %w", err)
+	}
+
+	migrations := make([]migration, 0, len(paths))
+
+	for _, path := range paths {
+		prefix := path
+		if i := strings.IndexByte(path, '_'); i > 0 {
+			prefix = path[:i]
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 32)
+		if err != nil {
+			continue
+		}
+
+		body, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite load migration: %w", err)
+		}
+
+		pieces := strings.SplitN(string(body), migrationSeparator, 2)
+
+		m := migration{version: int32(version), name: path, up: strings.TrimSpace(pieces[0])}
+		if len(pieces) == 2 {
+			m.down = strings.TrimSpace(pieces[1])
+		}
+
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	for i, m := range migrations {
+		if m.version != int32(i+1) {
+			return nil, fmt.Errorf("sqlite load migrations: %s, expected version %d: %w", m.name, i+1, database.ErrBadVersion)
+		}
+	}
+
+	return migrations, nil
+}
@@ -0,0 +1,588 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+
+	// Registers the sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const defaultBusyTimeout = 5 * time.Second
+
+type DatabaseService struct {
+	db         *sql.DB
+	migrations []migration
+}
+
+type Config struct {
+	// Path is the database file, created when missing.
+	Path string
+	// BusyTimeout is how long a write waits for the one in progress, as
+	// SQLite runs a single writer at a time.
+	BusyTimeout time.Duration
+
+	// Migrations holds the numbered migration files at its root.
+	Migrations fs.FS
+}
+
+// NewDatabaseService opens the database in WAL mode, so that reads do not
+// block on the writer, with foreign keys enforced and write transactions
+// taking the lock up front instead of failing on upgrade.
+func NewDatabaseService(cfg Config) (*DatabaseService, error) {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = defaultBusyTimeout
+	}
+
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_synchronous=NORMAL&_foreign_keys=on&_txlock=immediate&_busy_timeout=%d",
+		cfg.Path, busyTimeout.Milliseconds())
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite open: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("sqlite open %s: %w", cfg.Path, err)
+	}
+
+	ds := &DatabaseService{db: db}
+
+	// Loaded once, the readiness check compares with them on every probe.
+	if cfg.Migrations != nil {
+		if ds.migrations, err = loadMigrations(cfg.Migrations); err != nil {
+			db.Close()
+
+			return nil, err
+		}
+	}
+
+	return ds, nil
+}
+
+func (ds *DatabaseService) Close() {
+	ds.db.Close()
+}
+
+func (ds *DatabaseService) Ping(ctx context.Context) error {
+	if err := ds.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("sqlite ping: %w", err)
+	}
+
+	return nil
+}
+
+// CheckWritable fails when the database takes no writes, being read-only
+// or locked by another writer for longer than the busy timeout. The probe
+// write changes no rows and is rolled back.
+func (ds *DatabaseService) CheckWritable(ctx context.Context) error {
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite start write probe: %w", err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, "update board set title=title where 0"); err != nil {
+		return fmt.Errorf("sqlite write probe: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) GetBoardList(ctx context.Context) (models.BoardList, error) {
+	rows, err := ds.db.QueryContext(ctx, "select id, title from board where status>0 order by id")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite select boards: %w", err)
+	}
+	defer rows.Close()
+
+	boards := models.BoardList{}
+
+	for rows.Next() {
+		b := models.Board{}
+
+		if err := rows.Scan(&b.ID, &b.Title); err != nil {
+			return nil, fmt.Errorf("sqlite scan boards: %w", err)
+		}
+
+		boards = append(boards, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite select boards: %w", err)
+	}
+
+	return boards, nil
+}
+
+func (ds *DatabaseService) GetBoard(ctx context.Context, boardID uint64) (*models.Board, error) {
+	board := new(models.Board)
+
+	row := ds.db.QueryRowContext(ctx, "select id, title, updated from board where status>0 and id=? limit 1", boardID)
+
+	if err := row.Scan(&board.ID, &board.Title, &board.Updated); err != nil {
+		return nil, fmt.Errorf("sqlite select board: %w", notFound(err))
+	}
+
+	threads, err := queryMessages(ctx, ds.db, "select id, title, text, content, created, coalesce(poster_id, ''), sage, updated from message where status>0 and board_id=? order by bumped desc, id", boardID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite select board threads: %w", err)
+	}
+
+	if len(threads) > 0 {
+		board.Threads = threads
+	}
+
+	return board, nil
+}
+
+func (ds *DatabaseService) GetBoardSettings(ctx context.Context, boardID uint64) (*models.BoardSettings, error) {
+	row := ds.db.QueryRowContext(ctx, "select poster_ids, show_sage from board where status>0 and id=? limit 1", boardID)
+
+	settings := new(models.BoardSettings)
+
+	if err := row.Scan(&settings.PosterIDs, &settings.ShowSage); err != nil {
+		return nil, fmt.Errorf("sqlite select board settings: %w", notFound(err))
+	}
+
+	return settings, nil
+}
+
+func (ds *DatabaseService) GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error) {
+	messages, err := queryMessages(ctx, ds.db, "select id, title, text, content, created, coalesce(poster_id, ''), sage, updated from message where status>0 and board_id=? and thread_id=? order by id", boardID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite select comments: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (ds *DatabaseService) PostThread(ctx context.Context, thread *models.Message, posterID database.PosterIDFunc) (uint64, uint64, error) {
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("sqlite start tx for post thread: %w", err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx, "insert into thread (board_id) values (?)", thread.BoardID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("sqlite insert thread: %w", err)
+	}
+
+	threadID, err := res.LastInsertId()
+	if err != nil {
+		return 0, 0, fmt.Errorf("sqlite insert thread: %w", err)
+	}
+
+	var pid string
+
+	if posterID != nil {
+		if pid, err = posterID(uint64(threadID)); err != nil {
+			return 0, 0, fmt.Errorf("sqlite make thread poster id: %w", err)
+		}
+	}
+
+	now := time.Now().UTC()
+
+	res, err = tx.ExecContext(ctx, "insert into message (status, board_id, thread_id, title, text, content, created, bumped, ip_hash, ip_encrypted, poster_id, deletion_hash) values (1, ?, ?, ?, ?, ?, ?, ?, nullif(?, ''), ?, nullif(?, ''), nullif(?, ''))",
+		thread.BoardID, threadID, thread.Title, thread.Text, thread.Content, now, now, thread.IPHash, thread.IPEncrypted, pid, thread.DeletionHash)
+	if err != nil {
+		return 0, 0, fmt.Errorf("sqlite insert thread message: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, 0, fmt.Errorf("sqlite insert thread message: %w", err)
+	}
+
+	if err := touch(ctx, tx, thread.BoardID, uint64(id), now); err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("sqlite commit tx for post thread: %w", err)
+	}
+
+	return uint64(id), uint64(threadID), nil
+}
+
+func (ds *DatabaseService) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite start tx for post message: %w", err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now().UTC()
+
+	res, err := tx.ExecContext(ctx, "insert into message (status, board_id, thread_id, title, text, content, created, bumped, ip_hash, ip_encrypted, poster_id, deletion_hash, sage) values (1, ?, ?, ?, ?, ?, ?, ?, nullif(?, ''), ?, nullif(?, ''), nullif(?, ''), ?)",
+		message.BoardID, message.ThreadID, message.Title, message.Text, message.Content, now, now, message.IPHash, message.IPEncrypted, message.PosterID, message.DeletionHash, message.Sage)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite insert message: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("sqlite insert message: %w", err)
+	}
+
+	if err := touch(ctx, tx, message.BoardID, uint64(id), now); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("sqlite commit tx for post message: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (ds *DatabaseService) BumpThread(ctx context.Context, boardID, threadID uint64) error {
+	if _, err := ds.db.ExecContext(ctx, "update message set bumped=? where board_id=? and thread_id=?", time.Now().UTC(), boardID, threadID); err != nil {
+		return fmt.Errorf("sqlite bump thread: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) GetMessage(ctx context.Context, boardID, messageID uint64) (*models.Message, error) {
+	row := ds.db.QueryRowContext(ctx, "select id, board_id, thread_id, title, text, content, created, coalesce(poster_id, ''), coalesce(ip_hash, ''), ip_encrypted, coalesce(deletion_hash, '') from message where status>0 and board_id=? and id=? limit 1", boardID, messageID)
+
+	m := new(models.Message)
+
+	if err := row.Scan(&m.ID, &m.BoardID, &m.ThreadID, &m.Title, &m.Text, &m.Content, &m.Created, &m.PosterID, &m.IPHash, &m.IPEncrypted, &m.DeletionHash); err != nil {
+		return nil, fmt.Errorf("sqlite select message: %w", notFound(err))
+	}
+
+	return m, nil
+}
+
+func (ds *DatabaseService) DeleteMessage(ctx context.Context, boardID, messageID uint64) error {
+	return ds.updateMessage(ctx, "delete message", boardID, messageID, "update message set status=? where board_id=? and id=?", models.Deleted, boardID, messageID)
+}
+
+func (ds *DatabaseService) DeleteMessageContent(ctx context.Context, boardID, messageID uint64) error {
+	return ds.updateMessage(ctx, "delete message content", boardID, messageID, "update message set content='' where board_id=? and id=?", boardID, messageID)
+}
+
+// updateMessage runs the update of a message together with the touch of
+// its thread and board.
+func (ds *DatabaseService) updateMessage(ctx context.Context, action string, boardID, messageID uint64, query string, args ...interface{}) error {
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite start tx for %s: %w", action, err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlite %s: %w", action, err)
+	}
+
+	if err := touch(ctx, tx, boardID, messageID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite commit tx for %s: %w", action, err)
+	}
+
+	return nil
+}
+
+// touch marks the thread of the message and its board as changed, which
+// moves the Last-Modified of both.
+func touch(ctx context.Context, db execer, boardID, messageID uint64, now time.Time) error {
+	if _, err := db.ExecContext(ctx, "update message set updated=? where board_id=? and thread_id=(select thread_id from message where board_id=? and id=?)", now, boardID, boardID, messageID); err != nil {
+		return fmt.Errorf("sqlite touch thread: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, "update board set updated=? where id=?", now, boardID); err != nil {
+		return fmt.Errorf("sqlite touch board: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) PurgeMessageIPs(ctx context.Context, before time.Time) (int64, error) {
+	res, err := ds.db.ExecContext(ctx, "update message set ip_encrypted=null where ip_encrypted is not null and created<?", before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("sqlite purge message ips: %w", err)
+	}
+
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("sqlite purge message ips: %w", err)
+	}
+
+	return purged, nil
+}
+
+func (ds *DatabaseService) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	res, err := ds.db.ExecContext(ctx, "insert into report (status, board_id, message_id, reason, comment, created) values (?, ?, ?, ?, ?, ?)",
+		models.ReportOpen, report.BoardID, report.MessageID, report.Reason, report.Comment, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("sqlite insert report: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("sqlite insert report: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (ds *DatabaseService) GetReport(ctx context.Context, reportID uint64) (*models.Report, error) {
+	row := ds.db.QueryRowContext(ctx, "select id, board_id, message_id, reason, comment, status, created, resolved from report where id=? limit 1", reportID)
+
+	r := new(models.Report)
+
+	if err := row.Scan(&r.ID, &r.BoardID, &r.MessageID, &r.Reason, &r.Comment, &r.Status, &r.Created, &r.Resolved); err != nil {
+		return nil, fmt.Errorf("sqlite select report: %w", notFound(err))
+	}
+
+	return r, nil
+}
+
+func (ds *DatabaseService) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	rows, err := ds.db.QueryContext(ctx, "select id, board_id, message_id, reason, comment, status, created, resolved from report where status=? order by id", status)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite select reports: %w", err)
+	}
+
+	defer rows.Close()
+
+	reports := models.ReportList{}
+
+	for rows.Next() {
+		r := models.Report{}
+
+		if err := rows.Scan(&r.ID, &r.BoardID, &r.MessageID, &r.Reason, &r.Comment, &r.Status, &r.Created, &r.Resolved); err != nil {
+			return nil, fmt.Errorf("sqlite scan report: %w", err)
+		}
+
+		reports = append(reports, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite select reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (ds *DatabaseService) ResolveReport(ctx context.Context, reportID uint64, status int) error {
+	return resolveReport(ctx, ds.db, reportID, status)
+}
+
+func (ds *DatabaseService) ActOnReport(ctx context.Context, report *models.Report) error {
+	tx, err := ds.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlite start tx for act on report: %w", err)
+	}
+
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := resolveReport(ctx, tx, report.ID, models.ReportActioned); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "update message set status=? where board_id=? and id=?", models.Deleted, report.BoardID, report.MessageID); err != nil {
+		return fmt.Errorf("sqlite delete reported message: %w", err)
+	}
+
+	if err := touch(ctx, tx, report.BoardID, report.MessageID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "update report set status=?, resolved=? where message_id=? and status=?", models.ReportActioned, time.Now().UTC(), report.MessageID, models.ReportOpen); err != nil {
+		return fmt.Errorf("sqlite resolve message reports: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlite commit tx for act on report: %w", err)
+	}
+
+	return nil
+}
+
+// resolveReport resolves the report only while it is open, so that of two
+// moderators resolving it at once only one succeeds.
+func resolveReport(ctx context.Context, db execer, reportID uint64, status int) error {
+	res, err := db.ExecContext(ctx, "update report set status=?, resolved=? where id=? and status=?", status, time.Now().UTC(), reportID, models.ReportOpen)
+	if err != nil {
+		return fmt.Errorf("sqlite resolve report: %w", err)
+	}
+
+	resolved, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlite resolve report: %w", err)
+	}
+
+	if resolved == 0 {
+		return fmt.Errorf("sqlite resolve report %d: %w", reportID, database.ErrConflict)
+	}
+
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (ds *DatabaseService) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	network := ban.Network
+	if network != "" {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return 0, fmt.Errorf("sqlite insert ban: %w", err)
+		}
+
+		network = ipNet.String()
+	}
+
+	var expires *time.Time
+
+	if ban.Expires != nil {
+		utc := ban.Expires.UTC()
+		expires = &utc
+	}
+
+	res, err := ds.db.ExecContext(ctx, "insert into ban (status, board_id, network, ip_hash, reason, moderator, created, expires) values (1, nullif(?, 0), nullif(?, ''), nullif(?, ''), ?, ?, ?, ?)",
+		ban.BoardID, network, ban.IPHash, ban.Reason, ban.Moderator, time.Now().UTC(), expires)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite insert ban: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("sqlite insert ban: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (ds *DatabaseService) GetBanList(ctx context.Context) (models.BanList, error) {
+	bans, err := ds.queryBans(ctx, "select id, coalesce(board_id, 0), coalesce(network, ''), coalesce(ip_hash, ''), reason, moderator, created, expires from ban where status>0 and (expires is null or expires>?) order by id",
+		time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("sqlite select bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+// GetActiveBans selects the bans on the board or on every board, matching
+// the address hash in SQL and the address against the networks in Go, as
+// SQLite has no network type.
+func (ds *DatabaseService) GetActiveBans(ctx context.Context, ip, ipHash string, boardID uint64) (models.BanList, error) {
+	candidates, err := ds.queryBans(ctx, "select id, coalesce(board_id, 0), coalesce(network, ''), coalesce(ip_hash, ''), reason, moderator, created, expires from ban where status>0 and (expires is null or expires>?) and (board_id is null or board_id=?) and ((network is not null and ?<>'') or ip_hash=?) order by id",
+		time.Now().UTC(), boardID, ip, ipHash)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite select active bans: %w", err)
+	}
+
+	addr := net.ParseIP(ip)
+	bans := models.BanList{}
+
+	for _, b := range candidates {
+		if ipHash != "" && b.IPHash == ipHash {
+			bans = append(bans, b)
+
+			continue
+		}
+
+		if _, network, err := net.ParseCIDR(b.Network); err == nil && addr != nil && network.Contains(addr) {
+			bans = append(bans, b)
+		}
+	}
+
+	return bans, nil
+}
+
+func (ds *DatabaseService) DeleteBan(ctx context.Context, banID uint64) error {
+	res, err := ds.db.ExecContext(ctx, "update ban set status=? where status>0 and id=?", models.Deleted, banID)
+	if err != nil {
+		return fmt.Errorf("sqlite delete ban: %w", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlite delete ban: %w", err)
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("sqlite delete ban: %w", database.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (ds *DatabaseService) queryBans(ctx context.Context, query string, args ...interface{}) (models.BanList, error) {
+	rows, err := ds.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	defer rows.Close()
+
+	bans := models.BanList{}
+
+	for rows.Next() {
+		b := models.Ban{}
+
+		if err := rows.Scan(&b.ID, &b.BoardID, &b.Network, &b.IPHash, &b.Reason, &b.Moderator, &b.Created, &b.Expires); err != nil {
+			return nil, fmt.Errorf("scan ban: %w", err)
+		}
+
+		bans = append(bans, b)
+	}
+
+	return bans, rows.Err() //nolint:wrapcheck
+}
+
+func queryMessages(ctx context.Context, q *sql.DB, query string, args ...interface{}) (models.MessageList, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	defer rows.Close()
+
+	messages := models.MessageList{}
+
+	for rows.Next() {
+		m := models.Message{}
+
+		if err := rows.Scan(&m.ID, &m.Title, &m.Text, &m.Content, &m.Created, &m.PosterID, &m.Sage, &m.Updated); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err() //nolint:wrapcheck
+}
+
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.ErrNotFound
+	}
+
+	return err
+}
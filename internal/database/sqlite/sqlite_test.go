@@ -0,0 +1,153 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/databasetest"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/database/sqlite"
+	"github.com/Batyachelly/goBoard/migration"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	databasetest.Run(t, func(t *testing.T, boards ...databasetest.Board) database.Databaser {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "goboard.db")
+		ds := newDatabaseService(t, path)
+
+		require.NoError(t, ds.Migrate())
+
+		db, err := sql.Open("sqlite3", path)
+		require.NoError(t, err)
+
+		defer db.Close()
+
+		for _, b := range boards {
+			status := models.Active
+			if b.Deleted {
+				status = models.Deleted
+			}
+
+			_, err := db.Exec("insert into board (status, title, poster_ids, show_sage) values (?, ?, ?, ?)",
+				status, b.Title, b.Settings.PosterIDs, b.Settings.ShowSage)
+			require.NoError(t, err)
+		}
+
+		return ds
+	})
+}
+
+func TestDatabaseService_MigrateTo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ds := newDatabaseService(t, filepath.Join(t.TempDir(), "goboard.db"))
+
+	status, err := ds.MigrationStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(0), status.Current)
+	require.NotZero(t, status.Latest)
+	require.Error(t, ds.CheckMigrations(ctx))
+
+	require.NoError(t, ds.Migrate())
+	require.NoError(t, ds.CheckMigrations(ctx))
+
+	// Migrating again is a no-op.
+	require.NoError(t, ds.Migrate())
+
+	steps, err := ds.MigrationPlan(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, steps, int(status.Latest))
+	require.Equal(t, database.DirectionDown, steps[0].Direction)
+
+	require.NoError(t, ds.MigrateTo(ctx, 0))
+
+	status, err = ds.MigrationStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(0), status.Current)
+
+	_, err = ds.GetBoardList(ctx)
+	require.Error(t, err, "tables are dropped at version 0")
+
+	require.ErrorIs(t, ds.MigrateTo(ctx, status.Latest+1), database.ErrBadVersion)
+}
+
+func TestDatabaseService_CheckMigrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	migrations := fstest.MapFS{
+		"001_create_board.sql": {Data: []byte("create table board (id integer primary key, title text)")},
+	}
+
+	ds, err := sqlite.NewDatabaseService(sqlite.Config{Path: filepath.Join(t.TempDir(), "goboard.db"), Migrations: migrations})
+	require.NoError(t, err)
+
+	defer ds.Close()
+
+	require.NoError(t, ds.Migrate())
+
+	// The migrations are read once, not on every readiness probe.
+	migrations["002_create_message.sql"] = &fstest.MapFile{Data: []byte("create table message (id integer primary key)")}
+
+	require.NoError(t, ds.CheckMigrations(ctx))
+}
+
+func TestDatabaseService_CheckWritable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "goboard.db")
+
+	migrations, err := fs.Sub(migration.SQLite, "sqlite")
+	require.NoError(t, err)
+
+	ds, err := sqlite.NewDatabaseService(sqlite.Config{Path: path, BusyTimeout: 10 * time.Millisecond, Migrations: migrations})
+	require.NoError(t, err)
+
+	defer ds.Close()
+
+	require.NoError(t, ds.Migrate())
+	require.NoError(t, ds.CheckWritable(ctx))
+
+	// Another process holding the write lock past the busy timeout.
+	db, err := sql.Open("sqlite3", "file:"+path+"?_txlock=immediate")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	require.Error(t, ds.CheckWritable(ctx))
+
+	require.NoError(t, tx.Rollback())
+	require.NoError(t, ds.CheckWritable(ctx))
+}
+
+func newDatabaseService(t *testing.T, path string) *sqlite.DatabaseService {
+	t.Helper()
+
+	migrations, err := fs.Sub(migration.SQLite, "sqlite")
+	require.NoError(t, err)
+
+	ds, err := sqlite.NewDatabaseService(sqlite.Config{Path: path, Migrations: migrations})
+	require.NoError(t, err)
+
+	t.Cleanup(ds.Close)
+
+	return ds
+}
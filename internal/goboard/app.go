@@ -0,0 +1,325 @@
+package goboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/config"
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/pg"
+	"github.com/Batyachelly/goBoard/internal/database/sqlite"
+	"github.com/Batyachelly/goBoard/internal/health"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/logger/logrus"
+	"github.com/Batyachelly/goBoard/internal/logger/zap"
+	"github.com/Batyachelly/goBoard/internal/tracing"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/migration"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/multierr"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid config")
+	ErrNoMigrations  = errors.New("storage backend has no migrations")
+)
+
+// storage is a database backend owned by the App.
+type storage interface {
+	database.Databaser
+	Ping(ctx context.Context) error
+	// CheckWritable fails when the backend takes no writes.
+	CheckWritable(ctx context.Context) error
+	Close()
+}
+
+// App owns the long-lived dependencies of a goBoard process and stops them
+// in order: HTTP, gRPC and metrics servers first, then background workers, then the database.
+type App struct {
+	cfg *config.Config
+	log logger.Logger
+	db  storage
+
+	health        *health.Registry
+	tracer        *tracing.Tracer
+	httpServer    http.Serve
+	grpcServer    http.Serve
+	metricsServer http.Serve
+	workers       []func(ctx context.Context)
+}
+
+func newApp(configPath string) (*App, error) {
+	cfg, err := config.ParseConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	logLib, err := newLogger(cfg.General)
+	if err != nil {
+		return nil, err
+	}
+
+	tracer, err := newTracer(cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := newStorage(cfg, tracer)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := health.NewRegistry(cfg.HTTP.HealthTimeout)
+	registry.Register("database", db.Ping)
+	registry.Register("storage", db.CheckWritable)
+
+	if m, ok := db.(database.Migrator); ok {
+		registry.Register("migrations", m.CheckMigrations)
+	}
+
+	return &App{
+		cfg:    cfg,
+		log:    logLib,
+		db:     db,
+		health: registry,
+		tracer: tracer,
+	}, nil
+}
+
+// addWorker registers a background job running until its context is cancelled.
+func (a *App) addWorker(worker func(ctx context.Context)) {
+	a.workers = append(a.workers, worker)
+}
+
+// run serves HTTP, and gRPC and metrics when enabled, until SIGINT, SIGTERM or a server
+// failing. On a signal the readiness probe fails first, for the readiness
+// drain, while everything is still served. Then it drains in-flight requests
+// of every server for at most the configured shutdown timeout and waits for
+// the workers.
+func (a *App) run(ctx context.Context) error {
+	signalCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var wg sync.WaitGroup
+
+	for _, worker := range a.workers {
+		wg.Add(1)
+
+		go func(worker func(ctx context.Context)) {
+			defer wg.Done()
+
+			worker(workersCtx)
+		}(worker)
+	}
+
+	servers := []http.Serve{a.httpServer}
+
+	for _, server := range []http.Serve{a.grpcServer, a.metricsServer} {
+		if server != nil {
+			servers = append(servers, server)
+		}
+	}
+
+	serveErr := make(chan error, len(servers))
+
+	for _, server := range servers {
+		go func(server http.Serve) {
+			serveErr <- server.Serve()
+		}(server)
+	}
+
+	running := len(servers)
+
+	var err error
+
+	select {
+	case err = <-serveErr:
+		running--
+
+		a.health.SetShuttingDown()
+	case <-signalCtx.Done():
+		// A second signal kills the process without waiting.
+		stop()
+		a.log.Info("shutting down",
+			"readiness_drain", a.cfg.HTTP.ReadinessDrain.String(),
+			"drain_timeout", a.cfg.HTTP.ShutdownTimeout.String())
+
+		a.health.SetShuttingDown()
+
+		// Load balancers stop routing here once they see the failing probe,
+		// until then new requests are still served.
+		drain := time.NewTimer(a.cfg.HTTP.ReadinessDrain)
+
+		select {
+		case <-drain.C:
+		case err = <-serveErr:
+			running--
+
+			drain.Stop()
+		}
+	}
+
+	err = multierr.Append(err, a.shutdown(servers))
+
+	// A stopped server returns from Serve even when draining timed out.
+	for ; running > 0; running-- {
+		err = multierr.Append(err, <-serveErr)
+	}
+
+	stopWorkers()
+	wg.Wait()
+
+	return err
+}
+
+// shutdown drains all servers at once, so each gets the whole shutdown timeout.
+func (a *App) shutdown(servers []http.Serve) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	var group errgroup.Group
+
+	errs := make([]error, len(servers))
+
+	for i, server := range servers {
+		i, server := i, server
+
+		group.Go(func() error {
+			errs[i] = server.Shutdown(ctx)
+
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	return multierr.Combine(errs...)
+}
+
+func (a *App) close() {
+	a.db.Close()
+
+	if err := a.tracer.Shutdown(context.Background()); err != nil {
+		a.log.Error("shutdown tracer", "error", err)
+	}
+}
+
+func newStorage(cfg *config.Config, tracer *tracing.Tracer) (storage, error) {
+	switch cfg.Storage.Backend {
+	case "postgres":
+		db, err := pg.NewDatabaseService(pg.Config{
+			Host:         cfg.Postgres.Host,
+			Port:         cfg.Postgres.Port,
+			User:         cfg.Postgres.User,
+			Password:     cfg.Postgres.Password,
+			DB:           cfg.Postgres.DB,
+			SSLMode:      cfg.Postgres.SSLMode,
+			VersionTable: cfg.Postgres.VersionTable,
+
+			Migrations: migrations(cfg.Postgres.MigrationsPath),
+
+			Tracer: tracer,
+		})
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return db, nil
+	case "sqlite":
+		fsys, err := fs.Sub(migration.SQLite, "sqlite")
+		if err != nil {
+			return nil, fmt.Errorf("sqlite migrations: %w", err)
+		}
+
+		db, err := sqlite.NewDatabaseService(sqlite.Config{
+			Path:        cfg.SQLite.Path,
+			BusyTimeout: cfg.SQLite.BusyTimeout,
+			Migrations:  fsys,
+		})
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return db, nil
+	case "memory":
+		return memory.NewDatabaseService(memory.Config{Boards: cfg.Storage.MemoryBoards}), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q: %w", cfg.Storage.Backend, ErrInvalidConfig)
+	}
+}
+
+// migrations returns the migrations embedded in the binary unless a
+// directory overriding them is configured.
+func migrations(path string) fs.FS {
+	if path == "" {
+		return migration.FS
+	}
+
+	return os.DirFS(path)
+}
+
+func newLogger(cfg config.General) (logger.Logger, error) {
+	switch cfg.LogBackend {
+	case "logrus":
+		l, err := logrus.New(logrus.Config{Level: cfg.LogLevel, Format: cfg.LogFormat})
+		if err != nil {
+			return nil, fmt.Errorf("create logger: %w", err)
+		}
+
+		return l, nil
+	case "zap":
+		l, err := zap.New(zap.Config{Level: cfg.LogLevel, Format: cfg.LogFormat})
+		if err != nil {
+			return nil, fmt.Errorf("create logger: %w", err)
+		}
+
+		return l, nil
+	default:
+		return nil, fmt.Errorf("unknown log backend %q: %w", cfg.LogBackend, ErrInvalidConfig)
+	}
+}
+
+func newTracer(cfg config.Tracing) (*tracing.Tracer, error) {
+	var exporter sdktrace.SpanExporter
+
+	switch cfg.Exporter {
+	case "":
+		return tracing.NewTracer(tracing.Config{}), nil
+	case "otlp":
+		otlpExporter, err := tracing.NewOTLPExporter(context.Background(), cfg.OTLPEndpoint, cfg.OTLPInsecure)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		exporter = otlpExporter
+	case "stdout":
+		exporter = tracing.NewJSONExporter(os.Stdout)
+	case "file":
+		fileExporter, err := tracing.NewFileExporter(cfg.File)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		exporter = fileExporter
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q: %w", cfg.Exporter, ErrInvalidConfig)
+	}
+
+	return tracing.NewTracer(tracing.Config{
+		ServiceName: cfg.ServiceName,
+		Exporter:    exporter,
+	}), nil
+}
@@ -0,0 +1,326 @@
+package goboard
+
+import (
+	"context"
+	"errors"
+	"net"
+	nethttp "net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/config"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/health"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+// events records the steps of a shutdown in the order they happen.
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]string(nil), e.list...)
+}
+
+// blockingServer serves a handler that holds every request until release
+// is closed, recording when its shutdown starts and ends.
+type blockingServer struct {
+	server   *nethttp.Server
+	listener net.Listener
+	events   *events
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func newBlockingServer(t *testing.T, ev *events) *blockingServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &blockingServer{
+		listener: l,
+		events:   ev,
+		started:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+
+	s.server = &nethttp.Server{Handler: nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		close(s.started)
+		<-s.release
+		ev.add("request done")
+	})}
+
+	return s
+}
+
+// get sends a request and returns a channel receiving its status.
+func (s *blockingServer) get() <-chan int {
+	status := make(chan int, 1)
+
+	go func() {
+		resp, err := nethttp.Get("http://" + s.listener.Addr().String())
+		if err != nil {
+			status <- 0
+
+			return
+		}
+
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+
+	return status
+}
+
+func (s *blockingServer) Serve() error {
+	if err := s.server.Serve(s.listener); !errors.Is(err, nethttp.ErrServerClosed) {
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+func (s *blockingServer) Shutdown(ctx context.Context) error {
+	s.events.add("http shutdown")
+	err := s.server.Shutdown(ctx)
+	s.events.add("http drained")
+
+	return err //nolint:wrapcheck
+}
+
+// stubServer blocks in Serve until shut down, or fails at once with serveErr.
+type stubServer struct {
+	serveErr error
+	stopped  chan struct{}
+	once     sync.Once
+}
+
+func newStubServer(serveErr error) *stubServer {
+	return &stubServer{serveErr: serveErr, stopped: make(chan struct{})}
+}
+
+func (s *stubServer) Serve() error {
+	if s.serveErr != nil {
+		return s.serveErr
+	}
+
+	<-s.stopped
+
+	return nil
+}
+
+func (s *stubServer) Shutdown(context.Context) error {
+	s.once.Do(func() { close(s.stopped) })
+
+	return nil
+}
+
+func newTestApp(shutdownTimeout time.Duration, ev *events) *App {
+	cfg := &config.Config{}
+	cfg.HTTP.ShutdownTimeout = shutdownTimeout
+
+	a := &App{
+		cfg:    cfg,
+		log:    logger.TestLogger{},
+		health: health.NewRegistry(time.Second),
+	}
+
+	a.addWorker(func(ctx context.Context) {
+		<-ctx.Done()
+		ev.add("worker stopped")
+	})
+
+	return a
+}
+
+func TestApp_Run_Drain(t *testing.T) {
+	t.Parallel()
+
+	ev := &events{}
+	a := newTestApp(time.Minute, ev)
+	server := newBlockingServer(t, ev)
+	a.httpServer = server
+	a.grpcServer = newStubServer(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+
+	go func() {
+		runErr <- a.run(ctx)
+	}()
+
+	status := server.get()
+	<-server.started
+
+	// Cancelling the context stands in for SIGTERM.
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return len(ev.get()) > 0
+	}, 5*time.Second, time.Millisecond)
+
+	require.Equal(t, []string{"http shutdown"}, ev.get(), "workers run until the servers drain")
+	require.Equal(t, health.StatusFail, a.health.Check(context.Background()).Status)
+
+	close(server.release)
+
+	require.NoError(t, <-runErr)
+	require.Equal(t, nethttp.StatusOK, <-status)
+	require.Equal(t, []string{"http shutdown", "request done", "http drained", "worker stopped"}, ev.get())
+}
+
+// probeServer serves the readiness probe of the App.
+type probeServer struct {
+	server   *nethttp.Server
+	listener net.Listener
+	events   *events
+}
+
+func newProbeServer(t *testing.T, ev *events, registry *health.Registry) *probeServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := http.NewServer(http.Config{
+		Log:    logger.TestLogger{},
+		Health: registry,
+	}, usecase.NewUsecase(memory.NewDatabaseService(memory.Config{}), usecase.Config{}))
+
+	return &probeServer{
+		server:   &nethttp.Server{Handler: s.Handler()},
+		listener: l,
+		events:   ev,
+	}
+}
+
+// readyz returns the status of the readiness probe, 0 when it is not served.
+func (s *probeServer) readyz() int {
+	resp, err := nethttp.Get("http://" + s.listener.Addr().String() + "/readyz")
+	if err != nil {
+		return 0
+	}
+
+	resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func (s *probeServer) Serve() error {
+	if err := s.server.Serve(s.listener); !errors.Is(err, nethttp.ErrServerClosed) {
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+func (s *probeServer) Shutdown(ctx context.Context) error {
+	s.events.add("http shutdown")
+
+	return s.server.Shutdown(ctx) //nolint:wrapcheck
+}
+
+func TestApp_Run_ReadinessDrain(t *testing.T) {
+	t.Parallel()
+
+	const drain = 500 * time.Millisecond
+
+	ev := &events{}
+	a := newTestApp(time.Minute, ev)
+	a.cfg.HTTP.ReadinessDrain = drain
+	server := newProbeServer(t, ev, a.health)
+	a.httpServer = server
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runErr := make(chan error, 1)
+
+	go func() {
+		runErr <- a.run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return server.readyz() == nethttp.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Cancelling the context stands in for SIGTERM.
+	signalled := time.Now()
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return server.readyz() == nethttp.StatusServiceUnavailable
+	}, drain, 10*time.Millisecond, "the failing probe is served during the drain")
+	require.Empty(t, ev.get(), "the servers shut down after the drain")
+
+	require.NoError(t, <-runErr)
+	require.GreaterOrEqual(t, time.Since(signalled), drain)
+	require.Equal(t, []string{"http shutdown", "worker stopped"}, ev.get())
+	require.Zero(t, server.readyz())
+}
+
+func TestApp_Run_DrainTimeout(t *testing.T) {
+	t.Parallel()
+
+	ev := &events{}
+	a := newTestApp(10*time.Millisecond, ev)
+	server := newBlockingServer(t, ev)
+	a.httpServer = server
+
+	defer close(server.release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server.get()
+
+	go func() {
+		<-server.started
+		cancel()
+	}()
+
+	err := a.run(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []string{"http shutdown", "http drained", "worker stopped"}, ev.get())
+}
+
+func TestApp_Run_ServerFails(t *testing.T) {
+	t.Parallel()
+
+	errServe := errors.New("address in use")
+
+	ev := &events{}
+	a := newTestApp(time.Minute, ev)
+	grpcServer := newStubServer(nil)
+	a.httpServer = newStubServer(errServe)
+	a.grpcServer = grpcServer
+
+	err := a.run(context.Background())
+	require.ErrorIs(t, err, errServe)
+	require.Equal(t, []string{"worker stopped"}, ev.get())
+
+	select {
+	case <-grpcServer.stopped:
+	default:
+		t.Fatal("the other server was not shut down")
+	}
+}
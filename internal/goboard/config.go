@@ -0,0 +1,34 @@
+package goboard
+
+import (
+	"io"
+	"os"
+
+	"github.com/Batyachelly/goBoard/internal/config"
+)
+
+// ValidateConfig loads the effective configuration and reports every invalid setting.
+func ValidateConfig(configPath string) error {
+	_, err := config.ParseConfig(configPath)
+
+	return err //nolint:wrapcheck
+}
+
+// PrintConfig writes the effective configuration with secrets redacted,
+// followed by the validation result.
+func PrintConfig(configPath, format string, w io.Writer) error {
+	if configPath == "" {
+		configPath = os.Getenv(config.FileEnv)
+	}
+
+	cfg, err := config.Load(configPath, os.LookupEnv)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := cfg.Redacted().Write(w, format); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return cfg.Validate() //nolint:wrapcheck
+}
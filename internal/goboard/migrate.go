@@ -0,0 +1,165 @@
+package goboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/Batyachelly/goBoard/internal/config"
+	"github.com/Batyachelly/goBoard/internal/database"
+)
+
+// MigrateTarget picks the version to migrate to from the current status.
+type MigrateTarget func(status *database.MigrationStatus) int32
+
+// Latest targets the newest embedded migration.
+func Latest() MigrateTarget {
+	return func(status *database.MigrationStatus) int32 {
+		return status.Latest
+	}
+}
+
+// Steps targets a version relative to the current one, negative going down,
+// stopping at the first and latest versions.
+func Steps(n int32) MigrateTarget {
+	return func(status *database.MigrationStatus) int32 {
+		switch v := status.Current + n; {
+		case v < 0:
+			return 0
+		case v > status.Latest:
+			return status.Latest
+		default:
+			return v
+		}
+	}
+}
+
+// Version targets an absolute version, 0 reverting every migration.
+func Version(v int32) MigrateTarget {
+	return func(status *database.MigrationStatus) int32 {
+		return v
+	}
+}
+
+// MigrateTo runs the migrations needed to reach the target, printing every
+// step. With dryRun the steps and their SQL are printed but not run.
+func MigrateTo(configPath string, target MigrateTarget, dryRun bool, w io.Writer) error {
+	app, err := newMigrateApp(configPath)
+	if err != nil {
+		return err
+	}
+
+	defer app.close()
+
+	db, err := app.migrator()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	status, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	version := target(status)
+
+	steps, err := db.MigrationPlan(ctx, version)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(steps) == 0 {
+		fmt.Fprintf(w, "already at version %d\n", status.Current)
+
+		return nil
+	}
+
+	for _, step := range steps {
+		if dryRun {
+			fmt.Fprintf(w, "-- %s %s\n%s\n\n", step.Direction, step.Name, step.SQL)
+		} else {
+			fmt.Fprintf(w, "%s %s\n", step.Direction, step.Name)
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if err := db.MigrateTo(ctx, version); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	fmt.Fprintf(w, "migrated from version %d to %d\n", status.Current, version)
+
+	return nil
+}
+
+// MigrateStatus prints every migration with whether it is applied.
+func MigrateStatus(configPath string, w io.Writer) error {
+	app, err := newMigrateApp(configPath)
+	if err != nil {
+		return err
+	}
+
+	defer app.close()
+
+	db, err := app.migrator()
+	if err != nil {
+		return err
+	}
+
+	status, err := db.MigrationStatus(context.Background())
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATE")
+
+	for _, m := range status.Migrations {
+		state := "pending"
+		if m.Applied {
+			state = "applied"
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", m.Version, m.Name, state)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write migration status: %w", err)
+	}
+
+	fmt.Fprintf(w, "current version %d of %d\n", status.Current, status.Latest)
+
+	return nil
+}
+
+// newMigrateApp opens only the storage, validating only its settings, so the
+// migrate commands run without the serving configuration.
+func newMigrateApp(configPath string) (*App, error) {
+	cfg, err := config.ParseStorageConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := newStorage(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &App{cfg: cfg, db: db}, nil
+}
+
+// migrator returns the storage backend when it has versioned migrations.
+func (a *App) migrator() (database.Migrator, error) {
+	db, ok := a.db.(database.Migrator)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", a.cfg.Storage.Backend, ErrNoMigrations)
+	}
+
+	return db, nil
+}
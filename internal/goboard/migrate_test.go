@@ -0,0 +1,21 @@
+package goboard
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateStatus_StorageConfigOnly(t *testing.T) {
+	t.Setenv("HTTP_ADDR", "")
+	t.Setenv("STORAGE", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "goboard.db"))
+
+	require.Error(t, ValidateConfig(""), "the full configuration needs an HTTP address")
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, MigrateStatus("", buf))
+	require.Contains(t, buf.String(), "current version 0 of")
+}
@@ -0,0 +1,30 @@
+package goboard
+
+import (
+	"context"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+)
+
+// purgeIPs wipes expired poster addresses every interval until ctx is done.
+func purgeIPs(ctx context.Context, uc usecase.Usecaser, interval time.Duration, log logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := uc.PurgeMessageIPs(ctx)
+		if err != nil {
+			log.Error("purge message ips", "error", err)
+		} else if purged > 0 {
+			log.Info("purged expired poster addresses", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
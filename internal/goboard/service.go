@@ -1,96 +1,158 @@
 package goboard
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
 
-	"github.com/Batyachelly/goBoard/internal/config"
+	"github.com/Batyachelly/goBoard/internal/cache"
+	"github.com/Batyachelly/goBoard/internal/database"
 	"github.com/Batyachelly/goBoard/internal/database/pg"
-	"github.com/Batyachelly/goBoard/internal/logger/logrus"
+	"github.com/Batyachelly/goBoard/internal/metrics"
+	"github.com/Batyachelly/goBoard/internal/privacy"
+	"github.com/Batyachelly/goBoard/internal/tracing"
+	"github.com/Batyachelly/goBoard/internal/transport/grpc"
 	"github.com/Batyachelly/goBoard/internal/transport/http"
 	"github.com/Batyachelly/goBoard/internal/usecase"
 )
 
-type App struct {
-	httpServer http.Serve
-}
-
 // @title           GoBoard API
 // @version         0.1
 // @description     This is a goBoard server.
 
 // @host      localhost:8080
 // @BasePath  /api/v1
-func Serve(migrate bool) {
-	cfg, err := config.ParseConfig()
+
+// @securityDefinitions.apikey  ModeratorToken
+// @in                          header
+// @name                        Authorization
+func Serve(configPath string, migrate bool) error {
+	app, err := newApp(configPath)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	logLib, err := logrus.New(logrus.Config{
-		Level: cfg.General.LogLevel,
-	})
-	if err != nil {
-		log.Fatal(err)
+	defer app.close()
+
+	if migrate {
+		if err := app.db.Migrate(); err != nil {
+			return err //nolint:wrapcheck
+		}
 	}
 
-	databaseService, err := pg.NewDatabaseService(pg.Config{
-		Host:         cfg.Postgres.Host,
-		Port:         cfg.Postgres.Port,
-		User:         cfg.Postgres.User,
-		Password:     cfg.Postgres.Password,
-		DB:           cfg.Postgres.DB,
-		SSLMode:      cfg.Postgres.SSLMode,
-		VersionTable: cfg.Postgres.VersionTable,
-	})
-	if err != nil {
-		logLib.Fatal("%v", err)
+	cfg := app.cfg
+
+	ucCfg := usecase.Config{
+		IPRetention:    cfg.Privacy.IPRetention,
+		DeletionWindow: cfg.Posting.DeletionWindow,
+		MaxWaiters:     cfg.Posting.MaxThreadWaiters,
 	}
 
-	if migrate {
-		if err := databaseService.Migrate(); err != nil {
-			logLib.Fatal("%v", err)
+	if cfg.Privacy.IPHashKey != "" || cfg.Privacy.IPEncryptionKey != "" {
+		ucCfg.IPProtector, err = privacy.NewIPProtector(privacy.Config{
+			HashKey:       cfg.Privacy.IPHashKey,
+			EncryptionKey: cfg.Privacy.IPEncryptionKey,
+		})
+		if err != nil {
+			return fmt.Errorf("create ip protector: %w", err)
 		}
+	} else {
+		app.log.Info("ip keys are not configured, poster addresses will not be recorded")
 	}
 
-	uc := usecase.NewUsecase(databaseService)
-	hs := http.NewServer(http.Config{
-		Addr:         cfg.HTTP.Addr,
-		WriteTimeout: cfg.HTTP.WriteTimeout,
-		ReadTimeout:  cfg.HTTP.ReadTimeout,
-		Log:          logLib,
-	}, uc)
-	app := App{httpServer: hs}
+	var (
+		db database.Databaser = app.db
+		uc usecase.Usecaser
+		m  *metrics.Metrics
+	)
 
-	logLib.Fatal("%v", app.httpServer.Serve())
-}
+	if cfg.Metrics.Enabled {
+		m = metrics.New()
 
-func Migrate() {
-	cfg, err := config.ParseConfig()
-	if err != nil {
-		log.Fatal(err)
+		if pgDB, ok := app.db.(*pg.DatabaseService); ok {
+			if err := m.Register(metrics.NewPoolCollector(pgDB.Stat)); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+
+		db = metrics.NewDatabaser(db, m)
 	}
 
-	logLib, err := logrus.New(logrus.Config{
-		Level: cfg.General.LogLevel,
-	})
-	if err != nil {
-		log.Fatal(err)
+	if cfg.Cache.Enabled {
+		db = cache.NewDatabaser(db, cache.Config{Size: cfg.Cache.Size, TTL: cfg.Cache.TTL})
+	}
+
+	uc = usecase.NewUsecase(db, ucCfg)
+	if m != nil {
+		uc = metrics.NewUsecaser(uc, m)
 	}
 
-	databaseService, err := pg.NewDatabaseService(pg.Config{
-		Host:         cfg.Postgres.Host,
-		Port:         cfg.Postgres.Port,
-		User:         cfg.Postgres.User,
-		Password:     cfg.Postgres.Password,
-		DB:           cfg.Postgres.DB,
-		SSLMode:      cfg.Postgres.SSLMode,
-		VersionTable: cfg.Postgres.VersionTable,
-	})
+	uc = tracing.NewUsecaser(uc, app.tracer)
+
+	if ucCfg.IPProtector != nil {
+		app.addWorker(func(ctx context.Context) {
+			purgeIPs(ctx, uc, cfg.Privacy.IPPurgeInterval, app.log)
+		})
+	}
+
+	metricsPath := cfg.Metrics.Path
+	if m != nil && cfg.Metrics.Addr != "" {
+		app.metricsServer = m.NewServer(cfg.Metrics.Addr, cfg.Metrics.Path)
+		metricsPath = ""
+	}
+
+	socketMode, err := strconv.ParseUint(cfg.HTTP.SocketMode, 8, 32)
 	if err != nil {
-		logLib.Fatal("%v", err)
+		return fmt.Errorf("parse socket mode: %w", err)
 	}
 
-	if err := databaseService.Migrate(); err != nil {
-		logLib.Fatal("%v", err)
+	app.httpServer = http.NewServer(http.Config{
+		Addr:         cfg.HTTP.Addr,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		Log:          app.log,
+		Health:       app.health,
+		Metrics:      m,
+		Tracer:       app.tracer,
+		MetricsPath:  metricsPath,
+
+		Listener:          cfg.HTTP.Listener,
+		Socket:            cfg.HTTP.Socket,
+		SocketMode:        os.FileMode(socketMode),
+		TLSCert:           cfg.HTTP.TLSCert,
+		TLSKey:            cfg.HTTP.TLSKey,
+		TLSReloadInterval: cfg.HTTP.TLSReloadInterval,
+		H2C:               cfg.HTTP.H2C,
+		TrustedProxies:    cfg.HTTP.TrustedProxies,
+
+		Compress:        cfg.HTTP.Compress,
+		CompressMinSize: cfg.HTTP.CompressMinSize,
+		MaxBodySize:     int64(cfg.HTTP.MaxBodySize),
+		PostMaxBodySize: int64(cfg.HTTP.PostMaxBodySize),
+
+		CORS: http.CORSConfig{
+			Origins:     cfg.HTTP.CORSOrigins,
+			Methods:     cfg.HTTP.CORSMethods,
+			Headers:     cfg.HTTP.CORSHeaders,
+			Credentials: cfg.HTTP.CORSCredentials,
+			MaxAge:      cfg.HTTP.CORSMaxAge,
+		},
+		CSP:     cfg.HTTP.CSP,
+		DocsCSP: cfg.HTTP.DocsCSP,
+
+		ModeratorTokens: cfg.Moderation.Tokens,
+		ReportLimit:     cfg.Moderation.ReportLimit,
+		ReportPeriod:    cfg.Moderation.ReportPeriod,
+	}, uc)
+
+	if cfg.GRPC.Enabled {
+		app.grpcServer = grpc.NewServer(grpc.Config{
+			Addr:        cfg.GRPC.Addr,
+			Log:         app.log,
+			MaxRecvSize: cfg.GRPC.MaxRecvSize,
+		}, uc)
 	}
+
+	return app.run(context.Background())
 }
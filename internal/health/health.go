@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var ErrShuttingDown = errors.New("shutting down")
+
+// Check reports whether a dependency is usable, a nil error meaning healthy.
+type Check func(ctx context.Context) error
+
+// Registry holds the readiness checks of the subsystems, so each subsystem
+// can add its own without the HTTP layer knowing about it.
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []namedCheck
+	timeout time.Duration
+
+	shuttingDown int32
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"durationMs"`
+}
+
+// NewRegistry creates a registry running every check with the given timeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown makes every following readiness report fail, so that
+// load balancers stop routing to the process while it drains.
+func (r *Registry) SetShuttingDown() {
+	atomic.StoreInt32(&r.shuttingDown, 1)
+}
+
+// Check runs all registered checks concurrently and reports their results
+// in registration order.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]namedCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+
+	for i, c := range checks {
+		wg.Add(1)
+
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+
+			results[i] = run(ctx, c)
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+
+	if atomic.LoadInt32(&r.shuttingDown) == 1 {
+		report.Status = StatusFail
+		report.Checks = append(report.Checks, CheckResult{
+			Name:   "lifecycle",
+			Status: StatusFail,
+			Error:  ErrShuttingDown.Error(),
+		})
+	}
+
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func run(ctx context.Context, c namedCheck) CheckResult {
+	start := time.Now()
+	err := c.check(ctx)
+
+	result := CheckResult{
+		Name:       c.name,
+		Status:     StatusOK,
+		DurationMS: float64(time.Since(start)) / float64(time.Millisecond),
+	}
+
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
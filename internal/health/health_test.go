@@ -0,0 +1,55 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/health"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	t.Parallel()
+
+	r := health.NewRegistry(50 * time.Millisecond)
+
+	r.Register("ok", func(ctx context.Context) error {
+		return nil
+	})
+
+	report := r.Check(context.Background())
+	require.Equal(t, health.StatusOK, report.Status)
+	require.Len(t, report.Checks, 1)
+
+	r.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	r.Register("broken", func(ctx context.Context) error {
+		return errors.New("broken")
+	})
+
+	report = r.Check(context.Background())
+	require.Equal(t, health.StatusFail, report.Status)
+	require.Equal(t, []string{"ok", "slow", "broken"}, []string{
+		report.Checks[0].Name, report.Checks[1].Name, report.Checks[2].Name,
+	})
+	require.Equal(t, health.StatusOK, report.Checks[0].Status)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks[1].Error)
+	require.Equal(t, "broken", report.Checks[2].Error)
+}
+
+func TestRegistry_SetShuttingDown(t *testing.T) {
+	t.Parallel()
+
+	r := health.NewRegistry(time.Second)
+	r.SetShuttingDown()
+
+	report := r.Check(context.Background())
+	require.Equal(t, health.StatusFail, report.Status)
+	require.Equal(t, health.ErrShuttingDown.Error(), report.Checks[0].Error)
+}
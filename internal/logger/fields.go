@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// MissingValue is logged for a trailing key without a value.
+const MissingValue = "!MISSING"
+
+const maxRequestIDLen = 128
+
+type contextKey int
+
+const (
+	fieldsKey contextKey = iota
+	requestIDKey
+)
+
+// ContextWithFields returns ctx carrying the fields in addition to those
+// already stored, to be picked up by Logger.WithContext.
+func ContextWithFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	fields := FieldsFromContext(ctx)
+
+	merged := make([]interface{}, 0, len(fields)+len(keysAndValues))
+	merged = append(merged, fields...)
+	merged = append(merged, keysAndValues...)
+
+	return context.WithValue(ctx, fieldsKey, merged)
+}
+
+func FieldsFromContext(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(fieldsKey).([]interface{})
+
+	return fields
+}
+
+// ContextWithRequestID stores the request ID and adds it as the request_id field.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return ContextWithFields(context.WithValue(ctx, requestIDKey, requestID), "request_id", requestID)
+}
+
+// RequestID returns the incoming request ID when it is short and made of
+// safe characters, a new random one otherwise.
+func RequestID(incoming string) string {
+	if validRequestID(incoming) {
+		return incoming
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+
+	return id
+}
+
+// Fields converts alternating keys and values into a map, formatting
+// non-string keys and filling in a missing trailing value.
+func Fields(keysAndValues ...interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, (len(keysAndValues)+1)/2)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		var value interface{} = MissingValue
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		fields[key] = value
+	}
+
+	return fields
+}
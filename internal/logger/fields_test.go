@@ -0,0 +1,56 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		keysAndValues []interface{}
+		want          map[string]interface{}
+	}{
+		{
+			name:          "pairs",
+			keysAndValues: []interface{}{"board_id", 1, "error", errors.New("failed")},
+			want:          map[string]interface{}{"board_id": 1, "error": "failed"},
+		},
+		{
+			name:          "missing value",
+			keysAndValues: []interface{}{"board_id"},
+			want:          map[string]interface{}{"board_id": logger.MissingValue},
+		},
+		{
+			name:          "non-string key",
+			keysAndValues: []interface{}{1, "value"},
+			want:          map[string]interface{}{"1": "value"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, logger.Fields(tt.keysAndValues...))
+		})
+	}
+}
+
+func TestContextWithFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := logger.ContextWithRequestID(context.Background(), "abc")
+	ctx = logger.ContextWithFields(ctx, "trace_id", "def")
+
+	require.Equal(t, "abc", logger.RequestIDFromContext(ctx))
+	require.Equal(t, []interface{}{"request_id", "abc", "trace_id", "def"}, logger.FieldsFromContext(ctx))
+}
@@ -1,5 +1,7 @@
 package logger
 
+import "context"
+
 const (
 	DebugLevel = "DEBUG"
 	InfoLevel  = "INFO"
@@ -9,19 +11,30 @@ const (
 	FatalLevel = "FATAL"
 )
 
+// Logger writes leveled messages with structured fields given as
+// alternating keys and values, e.g. log.Error("get board", "board_id", id, "error", err).
 type Logger interface {
-	Info(string, ...interface{})
-	Debug(string, ...interface{})
-	Error(string, ...interface{})
-	Fatal(string, ...interface{})
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+	Fatal(msg string, keysAndValues ...interface{})
+
+	// With returns a child logger adding the fields to every message.
+	With(keysAndValues ...interface{}) Logger
+	// WithContext returns a child logger with the fields stored in ctx by ContextWithFields.
+	WithContext(ctx context.Context) Logger
 }
 
 type TestLogger struct{}
 
+func (t TestLogger) Debug(_ string, _ ...interface{}) {
+}
+
 func (t TestLogger) Info(_ string, _ ...interface{}) {
 }
 
-func (t TestLogger) Debug(_ string, _ ...interface{}) {
+func (t TestLogger) Warn(_ string, _ ...interface{}) {
 }
 
 func (t TestLogger) Error(_ string, _ ...interface{}) {
@@ -29,3 +42,11 @@ func (t TestLogger) Error(_ string, _ ...interface{}) {
 
 func (t TestLogger) Fatal(_ string, _ ...interface{}) {
 }
+
+func (t TestLogger) With(_ ...interface{}) Logger {
+	return t
+}
+
+func (t TestLogger) WithContext(_ context.Context) Logger {
+	return t
+}
@@ -1,54 +1,80 @@
 package logrus
 
 import (
+	"context"
+
 	"github.com/Batyachelly/goBoard/internal/logger"
 
 	log "github.com/sirupsen/logrus"
 )
 
 type Logrus struct {
-	sl *log.Logger
+	sl *log.Entry
 }
 
 type Config struct {
 	Level string
+	// Format is "json" or "text".
+	Format string
 }
 
 func New(cfg Config) (*Logrus, error) {
-	logrus := new(Logrus)
-
-	logrus.sl = log.New()
+	sl := log.New()
 
 	switch cfg.Level {
 	case logger.DebugLevel:
-		logrus.sl.SetLevel(log.DebugLevel)
+		sl.SetLevel(log.DebugLevel)
 	case logger.InfoLevel:
-		logrus.sl.SetLevel(log.InfoLevel)
+		sl.SetLevel(log.InfoLevel)
 	case logger.WarnLevel:
-		logrus.sl.SetLevel(log.WarnLevel)
+		sl.SetLevel(log.WarnLevel)
 	case logger.ErrorLevel:
-		logrus.sl.SetLevel(log.ErrorLevel)
+		sl.SetLevel(log.ErrorLevel)
 	case logger.PanicLevel:
-		logrus.sl.SetLevel(log.PanicLevel)
+		sl.SetLevel(log.PanicLevel)
 	case logger.FatalLevel:
-		logrus.sl.SetLevel(log.FatalLevel)
+		sl.SetLevel(log.FatalLevel)
+	}
+
+	if cfg.Format == "json" {
+		sl.SetFormatter(&log.JSONFormatter{})
 	}
 
-	return logrus, nil
+	return &Logrus{sl: log.NewEntry(sl)}, nil
+}
+
+func (l *Logrus) Debug(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues).Debug(msg)
+}
+
+func (l *Logrus) Info(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues).Info(msg)
+}
+
+func (l *Logrus) Warn(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues).Warn(msg)
 }
 
-func (l *Logrus) Info(template string, args ...interface{}) {
-	l.sl.Infof(template, args...)
+func (l *Logrus) Error(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues).Error(msg)
 }
 
-func (l *Logrus) Debug(template string, args ...interface{}) {
-	l.sl.Debugf(template, args...)
+func (l *Logrus) Fatal(msg string, keysAndValues ...interface{}) {
+	l.entry(keysAndValues).Fatal(msg)
 }
 
-func (l *Logrus) Error(template string, args ...interface{}) {
-	l.sl.Errorf(template, args...)
+func (l *Logrus) With(keysAndValues ...interface{}) logger.Logger {
+	return &Logrus{sl: l.entry(keysAndValues)}
 }
 
-func (l *Logrus) Fatal(template string, args ...interface{}) {
-	l.sl.Fatalf(template, args...)
+func (l *Logrus) WithContext(ctx context.Context) logger.Logger {
+	return l.With(logger.FieldsFromContext(ctx)...)
+}
+
+func (l *Logrus) entry(keysAndValues []interface{}) *log.Entry {
+	if len(keysAndValues) == 0 {
+		return l.sl
+	}
+
+	return l.sl.WithFields(logger.Fields(keysAndValues...))
 }
@@ -1,11 +1,13 @@
 package zap
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/Batyachelly/goBoard/internal/logger"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type Zaplog struct {
@@ -14,45 +16,63 @@ type Zaplog struct {
 
 type Config struct {
 	Level string
+	// Format is "json" or "text".
+	Format string
 }
 
 func New(cfg Config) (*Zaplog, error) {
-	zaplog := new(Zaplog)
+	zapCfg := zap.NewProductionConfig()
+	if cfg.Format != "json" {
+		zapCfg = zap.NewDevelopmentConfig()
+	}
 
 	switch cfg.Level {
 	case logger.DebugLevel:
-		devLog, err := zap.NewDevelopment()
-		if err != nil {
-			return nil, fmt.Errorf("zap new development: %w", err)
-		}
-
-		zaplog.sl = devLog.Sugar()
+		zapCfg.Level.SetLevel(zapcore.DebugLevel)
 	case logger.InfoLevel:
-		prodLog, err := zap.NewProduction()
-		if err != nil {
-			return nil, fmt.Errorf("zap new development: %w", err)
-		}
-
-		zaplog.sl = prodLog.Sugar()
-	default:
-		zaplog.sl = zap.NewExample().Sugar()
+		zapCfg.Level.SetLevel(zapcore.InfoLevel)
+	case logger.WarnLevel:
+		zapCfg.Level.SetLevel(zapcore.WarnLevel)
+	case logger.ErrorLevel:
+		zapCfg.Level.SetLevel(zapcore.ErrorLevel)
+	case logger.PanicLevel:
+		zapCfg.Level.SetLevel(zapcore.PanicLevel)
+	case logger.FatalLevel:
+		zapCfg.Level.SetLevel(zapcore.FatalLevel)
 	}
 
-	return zaplog, nil
+	zapLog, err := zapCfg.Build()
+	if err != nil {
+		return nil, fmt.Errorf("zap build logger: %w", err)
+	}
+
+	return &Zaplog{sl: zapLog.Sugar()}, nil
+}
+
+func (z *Zaplog) Debug(msg string, keysAndValues ...interface{}) {
+	z.sl.Debugw(msg, keysAndValues...)
+}
+
+func (z *Zaplog) Info(msg string, keysAndValues ...interface{}) {
+	z.sl.Infow(msg, keysAndValues...)
+}
+
+func (z *Zaplog) Warn(msg string, keysAndValues ...interface{}) {
+	z.sl.Warnw(msg, keysAndValues...)
 }
 
-func (z *Zaplog) Info(template string, args ...interface{}) {
-	z.sl.Infof(template, args...)
+func (z *Zaplog) Error(msg string, keysAndValues ...interface{}) {
+	z.sl.Errorw(msg, keysAndValues...)
 }
 
-func (z *Zaplog) Debug(template string, args ...interface{}) {
-	z.sl.Debugf(template, args...)
+func (z *Zaplog) Fatal(msg string, keysAndValues ...interface{}) {
+	z.sl.Fatalw(msg, keysAndValues...)
 }
 
-func (z *Zaplog) Error(template string, args ...interface{}) {
-	z.sl.Errorf(template, args...)
+func (z *Zaplog) With(keysAndValues ...interface{}) logger.Logger {
+	return &Zaplog{sl: z.sl.With(keysAndValues...)}
 }
 
-func (z *Zaplog) Fatal(template string, args ...interface{}) {
-	z.sl.Fatalf(template, args...)
+func (z *Zaplog) WithContext(ctx context.Context) logger.Logger {
+	return z.With(logger.FieldsFromContext(ctx)...)
 }
@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+)
+
+type databaser struct {
+	next database.Databaser
+	m    *Metrics
+}
+
+// NewDatabaser records the latency and errors of every database call.
+func NewDatabaser(next database.Databaser, m *Metrics) database.Databaser {
+	return &databaser{next: next, m: m}
+}
+
+func (d *databaser) observe(method string, start time.Time, err error) {
+	d.m.dbDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		d.m.dbErrors.WithLabelValues(method, usecase.Classify(err).String()).Inc()
+	}
+}
+
+func (d *databaser) Migrate() error {
+	start := time.Now()
+	err := d.next.Migrate()
+	d.observe("Migrate", start, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) GetBoardList(ctx context.Context) (models.BoardList, error) {
+	start := time.Now()
+	boards, err := d.next.GetBoardList(ctx)
+	d.observe("GetBoardList", start, err)
+
+	return boards, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetBoard(ctx context.Context, boardID uint64) (*models.Board, error) {
+	start := time.Now()
+	board, err := d.next.GetBoard(ctx, boardID)
+	d.observe("GetBoard", start, err)
+
+	return board, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetBoardSettings(ctx context.Context, boardID uint64) (*models.BoardSettings, error) {
+	start := time.Now()
+	settings, err := d.next.GetBoardSettings(ctx, boardID)
+	d.observe("GetBoardSettings", start, err)
+
+	return settings, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error) {
+	start := time.Now()
+	messages, err := d.next.GetThread(ctx, boardID, threadID)
+	d.observe("GetThread", start, err)
+
+	return messages, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetMessage(ctx context.Context, boardID, messageID uint64) (*models.Message, error) {
+	start := time.Now()
+	message, err := d.next.GetMessage(ctx, boardID, messageID)
+	d.observe("GetMessage", start, err)
+
+	return message, err //nolint:wrapcheck
+}
+
+func (d *databaser) PostThread(ctx context.Context, thread *models.Message, posterID database.PosterIDFunc) (uint64, uint64, error) {
+	start := time.Now()
+	id, threadID, err := d.next.PostThread(ctx, thread, posterID)
+	d.observe("PostThread", start, err)
+
+	return id, threadID, err //nolint:wrapcheck
+}
+
+func (d *databaser) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
+	start := time.Now()
+	id, err := d.next.PostMessage(ctx, message)
+	d.observe("PostMessage", start, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (d *databaser) BumpThread(ctx context.Context, boardID, threadID uint64) error {
+	start := time.Now()
+	err := d.next.BumpThread(ctx, boardID, threadID)
+	d.observe("BumpThread", start, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) DeleteMessage(ctx context.Context, boardID, messageID uint64) error {
+	start := time.Now()
+	err := d.next.DeleteMessage(ctx, boardID, messageID)
+	d.observe("DeleteMessage", start, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) DeleteMessageContent(ctx context.Context, boardID, messageID uint64) error {
+	start := time.Now()
+	err := d.next.DeleteMessageContent(ctx, boardID, messageID)
+	d.observe("DeleteMessageContent", start, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) PurgeMessageIPs(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := d.next.PurgeMessageIPs(ctx, before)
+	d.observe("PurgeMessageIPs", start, err)
+
+	return purged, err //nolint:wrapcheck
+}
+
+func (d *databaser) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	start := time.Now()
+	id, err := d.next.PostReport(ctx, report)
+	d.observe("PostReport", start, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetReport(ctx context.Context, reportID uint64) (*models.Report, error) {
+	start := time.Now()
+	report, err := d.next.GetReport(ctx, reportID)
+	d.observe("GetReport", start, err)
+
+	return report, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	start := time.Now()
+	reports, err := d.next.GetReportList(ctx, status)
+	d.observe("GetReportList", start, err)
+
+	return reports, err //nolint:wrapcheck
+}
+
+func (d *databaser) ResolveReport(ctx context.Context, reportID uint64, status int) error {
+	start := time.Now()
+	err := d.next.ResolveReport(ctx, reportID, status)
+	d.observe("ResolveReport", start, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) ActOnReport(ctx context.Context, report *models.Report) error {
+	start := time.Now()
+	err := d.next.ActOnReport(ctx, report)
+	d.observe("ActOnReport", start, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (d *databaser) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	start := time.Now()
+	id, err := d.next.PostBan(ctx, ban)
+	d.observe("PostBan", start, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetBanList(ctx context.Context) (models.BanList, error) {
+	start := time.Now()
+	bans, err := d.next.GetBanList(ctx)
+	d.observe("GetBanList", start, err)
+
+	return bans, err //nolint:wrapcheck
+}
+
+func (d *databaser) GetActiveBans(ctx context.Context, ip, ipHash string, boardID uint64) (models.BanList, error) {
+	start := time.Now()
+	bans, err := d.next.GetActiveBans(ctx, ip, ipHash, boardID)
+	d.observe("GetActiveBans", start, err)
+
+	return bans, err //nolint:wrapcheck
+}
+
+func (d *databaser) DeleteBan(ctx context.Context, banID uint64) error {
+	start := time.Now()
+	err := d.next.DeleteBan(ctx, banID)
+	d.observe("DeleteBan", start, err)
+
+	return err //nolint:wrapcheck
+}
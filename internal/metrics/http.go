@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/transport/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Middleware records request counts and latency labelled by the matched mux
+// route template, so that IDs in paths do not multiply the series.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unknown"
+
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+
+		rec := response.NewRecorder(w)
+		start := time.Now()
+
+		next.ServeHTTP(rec, r)
+
+		m.httpDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		m.httpRequests.WithLabelValues(route, r.Method, strconv.Itoa(rec.Status())).Inc()
+	})
+}
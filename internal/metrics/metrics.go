@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goboard"
+
+// Metrics owns a Prometheus registry and the collectors goBoard reports to it.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	postsCreated  *prometheus.CounterVec
+	usecaseErrors *prometheus.CounterVec
+
+	dbDuration *prometheus.HistogramVec
+	dbErrors   *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by route template, method and status code.",
+		}, []string{"route", "method", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route template and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+
+		postsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "posts_created_total",
+			Help:      "Threads and replies created by board.",
+		}, []string{"board", "type"}),
+		usecaseErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "usecase",
+			Name:      "errors_total",
+			Help:      "Usecase errors by method and error kind.",
+		}, []string{"method", "kind"}),
+
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Database call latency by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		dbErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "errors_total",
+			Help:      "Database call errors by method and error kind.",
+		}, []string{"method", "kind"}),
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.postsCreated,
+		m.usecaseErrors,
+		m.dbDuration,
+		m.dbErrors,
+	)
+
+	return m
+}
+
+// Register adds an extra collector, such as the database pool one.
+func (m *Metrics) Register(c prometheus.Collector) error {
+	if err := m.registry.Register(c); err != nil {
+		return fmt.Errorf("metrics register collector: %w", err)
+	}
+
+	return nil
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/metrics"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New()
+
+	r := mux.NewRouter()
+	r.Use(m.Middleware)
+	r.HandleFunc("/board/{board_id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/board/42", nil))
+
+	body := scrape(t, m)
+	require.Contains(t, body, `goboard_http_requests_total{code="404",method="GET",route="/board/{board_id}"} 1`)
+	require.Contains(t, body, `goboard_http_request_duration_seconds_count{method="GET",route="/board/{board_id}"} 1`)
+}
+
+func TestUsecaser(t *testing.T) {
+	t.Parallel()
+
+	ds := &mocks.Databaser{}
+	ds.On("GetBoard", mock.Anything, uint64(3)).Return(&models.Board{ID: 3}, nil)
+	ds.On("PostThread", mock.Anything, mock.Anything, mock.Anything).Return(uint64(1), uint64(1), nil)
+	ds.On("GetThread", mock.Anything, uint64(3), uint64(9)).Return(nil, database.ErrNotFound)
+
+	m := metrics.New()
+	uc := metrics.NewUsecaser(usecase.NewUsecase(metrics.NewDatabaser(ds, m), usecase.Config{}), m)
+
+	_, err := uc.PostThread(context.Background(), &models.Message{BoardID: 3, Text: "text"})
+	require.NoError(t, err)
+
+	_, err = uc.PostMessage(context.Background(), &models.Message{BoardID: 3, ThreadID: 9, Text: "text"})
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	body := scrape(t, m)
+	require.Contains(t, body, `goboard_posts_created_total{board="3",type="thread"} 1`)
+	require.Contains(t, body, `goboard_usecase_errors_total{kind="not_found",method="PostMessage"} 1`)
+	require.Contains(t, body, `goboard_db_errors_total{kind="not_found",method="GetThread"} 1`)
+	require.Contains(t, body, `goboard_db_query_duration_seconds_count{method="PostThread"} 1`)
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+
+	acquired        *prometheus.Desc
+	idle            *prometheus.Desc
+	total           *prometheus.Desc
+	max             *prometheus.Desc
+	acquireCount    *prometheus.Desc
+	acquireDuration *prometheus.Desc
+	emptyAcquire    *prometheus.Desc
+	canceledAcquire *prometheus.Desc
+}
+
+// NewPoolCollector exposes the statistics of a pgx connection pool,
+// read on every scrape from the stat function.
+func NewPoolCollector(stat func() *pgxpool.Stat) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		stat: stat,
+
+		acquired:        desc("acquired_conns", "Connections currently acquired from the pool."),
+		idle:            desc("idle_conns", "Idle connections in the pool."),
+		total:           desc("total_conns", "Connections currently open in the pool."),
+		max:             desc("max_conns", "Maximum size of the pool."),
+		acquireCount:    desc("acquires_total", "Successful connection acquires."),
+		acquireDuration: desc("acquire_wait_seconds_total", "Total time spent waiting for a connection."),
+		emptyAcquire:    desc("empty_acquires_total", "Acquires that had to wait because the pool was empty."),
+		canceledAcquire: desc("canceled_acquires_total", "Acquires cancelled by their context."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquire
+	ch <- c.canceledAcquire
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquire, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
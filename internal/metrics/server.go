@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+// Server serves the metrics alone, keeping them off the public API.
+type Server struct {
+	server *http.Server
+}
+
+// NewServer serves the registry at path on addr.
+func (m *Metrics) NewServer(addr, path string) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(path, m.Handler())
+
+	return &Server{server: &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: readHeaderTimeout}}
+}
+
+// Serve listens until the server fails or Shutdown is called, returning nil in the latter case.
+func (s *Server) Serve() error {
+	if err := s.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve metrics: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown metrics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+)
+
+type usecaser struct {
+	next usecase.Usecaser
+	m    *Metrics
+}
+
+// NewUsecaser counts the errors of every call by kind and the posts created per board.
+func NewUsecaser(next usecase.Usecaser, m *Metrics) usecase.Usecaser {
+	return &usecaser{next: next, m: m}
+}
+
+func (u *usecaser) observe(method string, err error) {
+	if err != nil {
+		u.m.usecaseErrors.WithLabelValues(method, usecase.Classify(err).String()).Inc()
+	}
+}
+
+func (u *usecaser) GetBoardList(ctx context.Context) (models.BoardList, error) {
+	boards, err := u.next.GetBoardList(ctx)
+	u.observe("GetBoardList", err)
+
+	return boards, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetBoard(ctx context.Context, boardID uint64) (*models.Board, error) {
+	board, err := u.next.GetBoard(ctx, boardID)
+	u.observe("GetBoard", err)
+
+	return board, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetThread(ctx context.Context, boardID, threadID uint64, filter models.ThreadFilter) (models.MessageList, error) {
+	messages, err := u.next.GetThread(ctx, boardID, threadID, filter)
+	u.observe("GetThread", err)
+
+	return messages, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostThread(ctx context.Context, thread *models.Message) (uint64, error) {
+	id, err := u.next.PostThread(ctx, thread)
+	u.observe("PostThread", err)
+
+	if err == nil {
+		u.m.postsCreated.WithLabelValues(strconv.FormatUint(thread.BoardID, 10), "thread").Inc()
+	}
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
+	id, err := u.next.PostMessage(ctx, message)
+	u.observe("PostMessage", err)
+
+	if err == nil {
+		u.m.postsCreated.WithLabelValues(strconv.FormatUint(message.BoardID, 10), "reply").Inc()
+	}
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) DeleteOwnMessage(ctx context.Context, boardID, messageID uint64, password string, contentOnly bool) error {
+	err := u.next.DeleteOwnMessage(ctx, boardID, messageID, password, contentOnly)
+	u.observe("DeleteOwnMessage", err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	id, err := u.next.PostReport(ctx, report)
+	u.observe("PostReport", err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	reports, err := u.next.GetReportList(ctx, status)
+	u.observe("GetReportList", err)
+
+	return reports, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetReportGroupList(ctx context.Context, status int) (models.ReportGroupList, error) {
+	groups, err := u.next.GetReportGroupList(ctx, status)
+	u.observe("GetReportGroupList", err)
+
+	return groups, err //nolint:wrapcheck
+}
+
+func (u *usecaser) DismissReport(ctx context.Context, reportID uint64) error {
+	err := u.next.DismissReport(ctx, reportID)
+	u.observe("DismissReport", err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) ActOnReport(ctx context.Context, reportID uint64) error {
+	err := u.next.ActOnReport(ctx, reportID)
+	u.observe("ActOnReport", err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetActiveBans(ctx context.Context, ip string, boardID uint64) (models.BanList, error) {
+	bans, err := u.next.GetActiveBans(ctx, ip, boardID)
+	u.observe("GetActiveBans", err)
+
+	return bans, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	id, err := u.next.PostBan(ctx, ban)
+	u.observe("PostBan", err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) BanMessageAuthor(ctx context.Context, boardID, messageID uint64, ban *models.Ban) (uint64, error) {
+	id, err := u.next.BanMessageAuthor(ctx, boardID, messageID, ban)
+	u.observe("BanMessageAuthor", err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetBanList(ctx context.Context) (models.BanList, error) {
+	bans, err := u.next.GetBanList(ctx)
+	u.observe("GetBanList", err)
+
+	return bans, err //nolint:wrapcheck
+}
+
+func (u *usecaser) DeleteBan(ctx context.Context, banID uint64) error {
+	err := u.next.DeleteBan(ctx, banID)
+	u.observe("DeleteBan", err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetMessageAuthor(ctx context.Context, boardID, messageID uint64) (*models.MessageAuthor, error) {
+	author, err := u.next.GetMessageAuthor(ctx, boardID, messageID)
+	u.observe("GetMessageAuthor", err)
+
+	return author, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PurgeMessageIPs(ctx context.Context) (int64, error) {
+	purged, err := u.next.PurgeMessageIPs(ctx)
+	u.observe("PurgeMessageIPs", err)
+
+	return purged, err //nolint:wrapcheck
+}
@@ -0,0 +1,139 @@
+package privacy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const posterIDLen = 8
+
+var (
+	ErrInvalidKey        = errors.New("invalid key")
+	ErrInvalidAddress    = errors.New("invalid ip address")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// IPProtector turns client addresses into a keyed hash, usable for matching
+// bans without the address itself, and an encrypted copy readable by moderators.
+type IPProtector struct {
+	hashKey []byte
+	aead    cipher.AEAD
+}
+
+type Config struct {
+	// HashKey is an arbitrary secret used for keyed hashing.
+	HashKey string
+	// EncryptionKey is a hex encoded 32 byte AES-256 key.
+	EncryptionKey string
+}
+
+func NewIPProtector(cfg Config) (*IPProtector, error) {
+	if cfg.HashKey == "" {
+		return nil, fmt.Errorf("privacy empty hash key: %w", ErrInvalidKey)
+	}
+
+	key, err := hex.DecodeString(cfg.EncryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("privacy encryption key must be 32 hex encoded bytes: %w", ErrInvalidKey)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("privacy new cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("privacy new gcm: %w", err)
+	}
+
+	return &IPProtector{
+		hashKey: []byte(cfg.HashKey),
+		aead:    aead,
+	}, nil
+}
+
+// Hash returns the hex encoded keyed hash of the canonical form of ip.
+func (p *IPProtector) Hash(ip string) (string, error) {
+	canonical, err := canonicalIP(ip)
+	if err != nil {
+		return "", err
+	}
+
+	return p.sum(canonical), nil
+}
+
+// Encrypt seals the canonical form of ip, prefixing the result with its nonce.
+func (p *IPProtector) Encrypt(ip string) ([]byte, error) {
+	canonical, err := canonicalIP(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, p.aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("privacy read nonce: %w", err)
+	}
+
+	return p.aead.Seal(nonce, nonce, []byte(canonical), nil), nil
+}
+
+func (p *IPProtector) Decrypt(ciphertext []byte) (string, error) {
+	nonceSize := p.aead.NonceSize()
+
+	if len(ciphertext) < nonceSize {
+		return "", fmt.Errorf("privacy short ciphertext: %w", ErrInvalidCiphertext)
+	}
+
+	plaintext, err := p.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("privacy open ciphertext: %w", ErrInvalidCiphertext)
+	}
+
+	return string(plaintext), nil
+}
+
+// PosterID returns a short ID for ip within a thread. The ID is stable for
+// the thread during a UTC day and cannot be linked across threads or days
+// without the hash key.
+func (p *IPProtector) PosterID(ip string, threadID uint64, day time.Time) (string, error) {
+	canonical, err := canonicalIP(ip)
+	if err != nil {
+		return "", err
+	}
+
+	sum := p.sum("poster", day.UTC().Format("2006-01-02"), strconv.FormatUint(threadID, 10), canonical)
+
+	return sum[:posterIDLen], nil
+}
+
+func (p *IPProtector) sum(parts ...string) string {
+	mac := hmac.New(sha256.New, p.hashKey)
+
+	for _, part := range parts {
+		mac.Write([]byte(part))
+		mac.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func canonicalIP(ip string) (string, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", fmt.Errorf("privacy parse %q: %w", ip, ErrInvalidAddress)
+	}
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,65 @@
+package privacy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/privacy"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPProtector(t *testing.T) {
+	t.Parallel()
+
+	p, err := privacy.NewIPProtector(privacy.Config{
+		HashKey:       "secret",
+		EncryptionKey: strings.Repeat("ab", 32),
+	})
+	require.NoError(t, err)
+
+	hash, err := p.Hash("2001:db8:0::1")
+	require.NoError(t, err)
+
+	sameHash, err := p.Hash("2001:db8::1")
+	require.NoError(t, err)
+	require.Equal(t, hash, sameHash)
+
+	ciphertext, err := p.Encrypt("192.0.2.1")
+	require.NoError(t, err)
+	require.NotContains(t, string(ciphertext), "192.0.2.1")
+
+	ip, err := p.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "192.0.2.1", ip)
+
+	ciphertext[len(ciphertext)-1] ^= 1
+
+	if _, err := p.Decrypt(ciphertext); !errors.Is(err, privacy.ErrInvalidCiphertext) {
+		t.Errorf("IPProtector.Decrypt() error = %v, wantErr %v", err, privacy.ErrInvalidCiphertext)
+	}
+
+	day := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	posterID, err := p.PosterID("192.0.2.1", 1, day)
+	require.NoError(t, err)
+	require.Len(t, posterID, 8)
+
+	samePosterID, err := p.PosterID("192.0.2.1", 1, day.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, posterID, samePosterID)
+
+	otherThreadID, err := p.PosterID("192.0.2.1", 2, day)
+	require.NoError(t, err)
+	require.NotEqual(t, posterID, otherThreadID)
+
+	nextDayID, err := p.PosterID("192.0.2.1", 1, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.NotEqual(t, posterID, nextDayID)
+
+	if _, err := p.Hash("not an ip"); !errors.Is(err, privacy.ErrInvalidAddress) {
+		t.Errorf("IPProtector.Hash() error = %v, wantErr %v", err, privacy.ErrInvalidAddress)
+	}
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// NewOTLPExporter sends spans over OTLP/HTTP to the collector at endpoint,
+// a host:port. Insecure sends them without TLS.
+func NewOTLPExporter(ctx context.Context, endpoint string, insecure bool) (*otlptrace.Exporter, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("tracing create otlp exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// SpanData is a finished span as written by the JSONExporter.
+type SpanData struct {
+	Service      string                 `json:"service"`
+	Name         string                 `json:"name"`
+	Kind         string                 `json:"kind"`
+	TraceID      string                 `json:"traceId"`
+	SpanID       string                 `json:"spanId"`
+	ParentSpanID string                 `json:"parentSpanId,omitempty"`
+	Start        time.Time              `json:"start"`
+	End          time.Time              `json:"end"`
+	DurationMS   float64                `json:"durationMs"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Status       string                 `json:"status"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// JSONExporter writes every span as one JSON object per line.
+type JSONExporter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{enc: json.NewEncoder(w)}
+}
+
+// NewFileExporter appends spans to the file at path, creating it if needed.
+func NewFileExporter(path string) (*JSONExporter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("tracing open span file: %w", err)
+	}
+
+	return &JSONExporter{enc: json.NewEncoder(f), closer: f}, nil
+}
+
+func (e *JSONExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, span := range spans {
+		if err := e.enc.Encode(spanData(span)); err != nil {
+			return fmt.Errorf("tracing encode span: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *JSONExporter) Shutdown(_ context.Context) error {
+	if e.closer == nil {
+		return nil
+	}
+
+	if err := e.closer.Close(); err != nil {
+		return fmt.Errorf("tracing close span file: %w", err)
+	}
+
+	return nil
+}
+
+func spanData(span sdktrace.ReadOnlySpan) SpanData {
+	data := SpanData{
+		Name:       span.Name(),
+		Kind:       span.SpanKind().String(),
+		TraceID:    span.SpanContext().TraceID().String(),
+		SpanID:     span.SpanContext().SpanID().String(),
+		Start:      span.StartTime(),
+		End:        span.EndTime(),
+		DurationMS: float64(span.EndTime().Sub(span.StartTime())) / float64(time.Millisecond),
+		Status:     "ok",
+	}
+
+	if service, ok := span.Resource().Set().Value(semconv.ServiceNameKey); ok {
+		data.Service = service.AsString()
+	}
+
+	if parent := span.Parent(); parent.IsValid() {
+		data.ParentSpanID = parent.SpanID().String()
+	}
+
+	if attrs := span.Attributes(); len(attrs) > 0 {
+		data.Attributes = make(map[string]interface{}, len(attrs))
+
+		for _, attr := range attrs {
+			data.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+		}
+	}
+
+	if status := span.Status(); status.Code == codes.Error {
+		data.Status = "error"
+		data.Error = status.Description
+	}
+
+	return data
+}
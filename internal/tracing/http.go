@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/Batyachelly/goBoard/internal/transport/response"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// unmatched names the spans of requests matching no route, keeping the
+// client controlled path out of span names.
+const unmatched = "unmatched"
+
+// Middleware starts a server span for every request, continuing the trace
+// of an incoming traceparent header. Spans are named after the matched mux
+// route template.
+func (t *Tracer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := unmatched
+		attrs := []attribute.KeyValue{
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPTargetKey.String(r.URL.RequestURI()),
+		}
+
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				name = r.Method + " " + tpl
+				attrs = append(attrs, semconv.HTTPRouteKey.String(tpl))
+			}
+		}
+
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := t.Start(ctx, name, trace.SpanKindServer, attrs...)
+		defer span.End()
+
+		rec := response.NewRecorder(w)
+
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(rec.Status()))
+
+		if rec.Status() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.Status()))
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentation = "github.com/Batyachelly/goBoard"
+
+// propagator reads the W3C traceparent header of incoming requests.
+var propagator = propagation.TraceContext{}
+
+var noopTracer = trace.NewNoopTracerProvider().Tracer(instrumentation)
+
+// Tracer starts the spans of goBoard on an OpenTelemetry tracer provider.
+// A nil *Tracer is valid and records nothing.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+type Config struct {
+	ServiceName string
+	Exporter    sdktrace.SpanExporter
+}
+
+// NewTracer creates a tracer batching spans to the configured exporter. The
+// spans of a remote parent are sampled as the parent is.
+func NewTracer(cfg Config) *Tracer {
+	if cfg.Exporter == nil {
+		return nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(cfg.Exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+		)),
+	)
+
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(instrumentation),
+	}
+}
+
+// Start begins a span that is a child of the span or remote span context
+// found in ctx, or the root of a new trace.
+func (t *Tracer) Start(ctx context.Context, name string, kind trace.SpanKind,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	tracer := noopTracer
+	if t != nil {
+		tracer = t.tracer
+	}
+
+	return tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// Shutdown exports the buffered spans and closes the exporter.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := t.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("tracing shutdown: %w", err)
+	}
+
+	return nil
+}
+
+// RecordError marks the span as failed, a nil error is ignored.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ID is the attribute of a database ID.
+func ID(key string, id uint64) attribute.KeyValue {
+	return attribute.Int64(key, int64(id))
+}
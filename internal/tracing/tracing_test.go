@@ -0,0 +1,152 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/tracing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// spans shuts the tracer down, flushing its batch, and decodes the spans.
+func spans(t *testing.T, tracer *tracing.Tracer, buf *bytes.Buffer) []tracing.SpanData {
+	t.Helper()
+
+	require.NoError(t, tracer.Shutdown(context.Background()))
+
+	var spans []tracing.SpanData
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var span tracing.SpanData
+		require.NoError(t, dec.Decode(&span))
+
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+func TestTracer_Middleware(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	tracer := tracing.NewTracer(tracing.Config{
+		ServiceName: "test",
+		Exporter:    tracing.NewJSONExporter(buf),
+	})
+
+	r := mux.NewRouter()
+	r.Use(tracer.Middleware)
+	r.HandleFunc("/board/{board_id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracer.Start(r.Context(), "usecase.GetBoard", trace.SpanKindInternal)
+		tracing.RecordError(span, errors.New("failed"))
+		span.End()
+
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/board/1", nil)
+	req.Header.Set("traceparent", traceparent)
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	got := spans(t, tracer, buf)
+	require.Len(t, got, 2)
+
+	child, server := got[0], got[1]
+
+	require.Equal(t, "GET /board/{board_id}", server.Name)
+	require.Equal(t, "test", server.Service)
+	require.Equal(t, trace.SpanKindServer.String(), server.Kind)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.TraceID)
+	require.Equal(t, "00f067aa0ba902b7", server.ParentSpanID)
+	require.Equal(t, "error", server.Status)
+	require.Equal(t, "/board/{board_id}", server.Attributes["http.route"])
+	require.EqualValues(t, http.StatusInternalServerError, server.Attributes["http.status_code"])
+
+	require.Equal(t, "usecase.GetBoard", child.Name)
+	require.Equal(t, server.TraceID, child.TraceID)
+	require.Equal(t, server.SpanID, child.ParentSpanID)
+	require.Equal(t, "failed", child.Error)
+}
+
+func TestTracer_Middleware_Unmatched(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	tracer := tracing.NewTracer(tracing.Config{Exporter: tracing.NewJSONExporter(buf)})
+
+	h := tracer.Middleware(http.NotFoundHandler())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/some/../random/path", nil))
+
+	got := spans(t, tracer, buf)
+	require.Len(t, got, 1)
+	require.Equal(t, "unmatched", got[0].Name)
+	require.NotContains(t, got[0].Attributes, "http.route")
+}
+
+func TestTracer_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		var tracer *tracing.Tracer
+
+		ctx, span := tracer.Start(context.Background(), "span", trace.SpanKindInternal)
+		tracing.RecordError(span, errors.New("failed"))
+		span.End()
+
+		require.False(t, span.IsRecording())
+		require.False(t, trace.SpanContextFromContext(ctx).IsValid())
+		require.NoError(t, tracer.Shutdown(context.Background()))
+	})
+
+	t.Run("unsampled parent", func(t *testing.T) {
+		t.Parallel()
+
+		buf := new(bytes.Buffer)
+		tracer := tracing.NewTracer(tracing.Config{Exporter: tracing.NewJSONExporter(buf)})
+
+		h := tracer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, span := tracer.Start(r.Context(), "span", trace.SpanKindInternal)
+			span.End()
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.Empty(t, spans(t, tracer, buf))
+	})
+
+	t.Run("new trace", func(t *testing.T) {
+		t.Parallel()
+
+		buf := new(bytes.Buffer)
+		tracer := tracing.NewTracer(tracing.Config{Exporter: tracing.NewJSONExporter(buf)})
+
+		ctx, span := tracer.Start(context.Background(), "span", trace.SpanKindInternal, tracing.ID("board.id", 7))
+		span.End()
+
+		require.Equal(t, span.SpanContext(), trace.SpanContextFromContext(ctx))
+
+		got := spans(t, tracer, buf)
+		require.Len(t, got, 1)
+		require.Empty(t, got[0].ParentSpanID)
+		require.Equal(t, "ok", got[0].Status)
+		require.EqualValues(t, 7, got[0].Attributes["board.id"])
+	})
+}
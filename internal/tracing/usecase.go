@@ -0,0 +1,200 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type usecaser struct {
+	next   usecase.Usecaser
+	tracer *Tracer
+}
+
+// NewUsecaser wraps every usecase call in an internal span.
+func NewUsecaser(next usecase.Usecaser, tracer *Tracer) usecase.Usecaser {
+	return &usecaser{next: next, tracer: tracer}
+}
+
+func (u *usecaser) GetBoardList(ctx context.Context) (models.BoardList, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetBoardList", trace.SpanKindInternal)
+	defer span.End()
+
+	boards, err := u.next.GetBoardList(ctx)
+	RecordError(span, err)
+
+	return boards, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetBoard(ctx context.Context, boardID uint64) (*models.Board, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetBoard", trace.SpanKindInternal, ID("board.id", boardID))
+	defer span.End()
+
+	board, err := u.next.GetBoard(ctx, boardID)
+	RecordError(span, err)
+
+	return board, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetThread(ctx context.Context, boardID, threadID uint64, filter models.ThreadFilter) (models.MessageList, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetThread", trace.SpanKindInternal, ID("board.id", boardID), ID("thread.id", threadID))
+	defer span.End()
+
+	messages, err := u.next.GetThread(ctx, boardID, threadID, filter)
+	RecordError(span, err)
+
+	return messages, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostThread(ctx context.Context, thread *models.Message) (uint64, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.PostThread", trace.SpanKindInternal, ID("board.id", thread.BoardID))
+	defer span.End()
+
+	id, err := u.next.PostThread(ctx, thread)
+	RecordError(span, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.PostMessage", trace.SpanKindInternal, ID("board.id", message.BoardID), ID("thread.id", message.ThreadID))
+	defer span.End()
+
+	id, err := u.next.PostMessage(ctx, message)
+	RecordError(span, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) DeleteOwnMessage(ctx context.Context, boardID, messageID uint64, password string, contentOnly bool) error {
+	ctx, span := u.tracer.Start(ctx, "usecase.DeleteOwnMessage", trace.SpanKindInternal, ID("board.id", boardID), ID("message.id", messageID))
+	defer span.End()
+
+	err := u.next.DeleteOwnMessage(ctx, boardID, messageID, password, contentOnly)
+	RecordError(span, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.PostReport", trace.SpanKindInternal, ID("board.id", report.BoardID), ID("message.id", report.MessageID))
+	defer span.End()
+
+	id, err := u.next.PostReport(ctx, report)
+	RecordError(span, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetReportList", trace.SpanKindInternal)
+	defer span.End()
+
+	reports, err := u.next.GetReportList(ctx, status)
+	RecordError(span, err)
+
+	return reports, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetReportGroupList(ctx context.Context, status int) (models.ReportGroupList, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetReportGroupList", trace.SpanKindInternal)
+	defer span.End()
+
+	groups, err := u.next.GetReportGroupList(ctx, status)
+	RecordError(span, err)
+
+	return groups, err //nolint:wrapcheck
+}
+
+func (u *usecaser) DismissReport(ctx context.Context, reportID uint64) error {
+	ctx, span := u.tracer.Start(ctx, "usecase.DismissReport", trace.SpanKindInternal, ID("report.id", reportID))
+	defer span.End()
+
+	err := u.next.DismissReport(ctx, reportID)
+	RecordError(span, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) ActOnReport(ctx context.Context, reportID uint64) error {
+	ctx, span := u.tracer.Start(ctx, "usecase.ActOnReport", trace.SpanKindInternal, ID("report.id", reportID))
+	defer span.End()
+
+	err := u.next.ActOnReport(ctx, reportID)
+	RecordError(span, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetActiveBans(ctx context.Context, ip string, boardID uint64) (models.BanList, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetActiveBans", trace.SpanKindInternal, ID("board.id", boardID))
+	defer span.End()
+
+	bans, err := u.next.GetActiveBans(ctx, ip, boardID)
+	RecordError(span, err)
+
+	return bans, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.PostBan", trace.SpanKindInternal)
+	defer span.End()
+
+	id, err := u.next.PostBan(ctx, ban)
+	RecordError(span, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) BanMessageAuthor(ctx context.Context, boardID, messageID uint64, ban *models.Ban) (uint64, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.BanMessageAuthor", trace.SpanKindInternal, ID("board.id", boardID), ID("message.id", messageID))
+	defer span.End()
+
+	id, err := u.next.BanMessageAuthor(ctx, boardID, messageID, ban)
+	RecordError(span, err)
+
+	return id, err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetBanList(ctx context.Context) (models.BanList, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetBanList", trace.SpanKindInternal)
+	defer span.End()
+
+	bans, err := u.next.GetBanList(ctx)
+	RecordError(span, err)
+
+	return bans, err //nolint:wrapcheck
+}
+
+func (u *usecaser) DeleteBan(ctx context.Context, banID uint64) error {
+	ctx, span := u.tracer.Start(ctx, "usecase.DeleteBan", trace.SpanKindInternal, ID("ban.id", banID))
+	defer span.End()
+
+	err := u.next.DeleteBan(ctx, banID)
+	RecordError(span, err)
+
+	return err //nolint:wrapcheck
+}
+
+func (u *usecaser) GetMessageAuthor(ctx context.Context, boardID, messageID uint64) (*models.MessageAuthor, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.GetMessageAuthor", trace.SpanKindInternal, ID("board.id", boardID), ID("message.id", messageID))
+	defer span.End()
+
+	author, err := u.next.GetMessageAuthor(ctx, boardID, messageID)
+	RecordError(span, err)
+
+	return author, err //nolint:wrapcheck
+}
+
+func (u *usecaser) PurgeMessageIPs(ctx context.Context) (int64, error) {
+	ctx, span := u.tracer.Start(ctx, "usecase.PurgeMessageIPs", trace.SpanKindInternal)
+	defer span.End()
+
+	purged, err := u.next.PurgeMessageIPs(ctx)
+	RecordError(span, err)
+
+	return purged, err //nolint:wrapcheck
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDMetadata is the metadata key of the request ID, the counterpart
+// of the X-Request-ID header of the HTTP API.
+const RequestIDMetadata = "x-request-id"
+
+// unaryAccessLog assigns every call an ID, reusing a sane incoming one,
+// returns it in the header metadata and logs the call once done.
+func (s *Server) unaryAccessLog(
+	ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, requestID := requestContext(ctx)
+
+	if err := grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, requestID)); err != nil {
+		s.log.WithContext(ctx).Error("set request id header", "error", err)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	s.logCall(ctx, info.FullMethod, err, start)
+
+	return resp, err
+}
+
+func (s *Server) streamAccessLog(
+	srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	ctx, requestID := requestContext(stream.Context())
+
+	if err := stream.SetHeader(metadata.Pairs(RequestIDMetadata, requestID)); err != nil {
+		s.log.WithContext(ctx).Error("set request id header", "error", err)
+	}
+
+	start := time.Now()
+	err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+
+	s.logCall(ctx, info.FullMethod, err, start)
+
+	return err
+}
+
+// requestContext adds the request ID to the context fields of the logger.
+func requestContext(ctx context.Context) (context.Context, string) {
+	var incoming string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadata); len(values) > 0 {
+			incoming = values[0]
+		}
+	}
+
+	requestID := logger.RequestID(incoming)
+
+	return logger.ContextWithRequestID(ctx, requestID), requestID
+}
+
+func (s *Server) logCall(ctx context.Context, method string, err error, start time.Time) {
+	s.log.WithContext(ctx).Info("request",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration_ms", float64(time.Since(start))/float64(time.Millisecond),
+		"remote", clientIP(ctx),
+	)
+}
+
+// contextStream replaces the context of a stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
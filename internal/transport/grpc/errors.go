@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorStatus translates the usecase error kinds to status codes. Unexpected
+// errors are logged by the caller and not shown to the client.
+func errorStatus(err error) error {
+	switch usecase.Classify(err) {
+	case usecase.KindInvalid:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case usecase.KindNotFound:
+		return status.Error(codes.NotFound, "not found")
+	case usecase.KindForbidden:
+		return status.Error(codes.PermissionDenied, usecase.ErrInvalidPassword.Error())
+	case usecase.KindConflict, usecase.KindUnprocessable:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case usecase.KindUnavailable:
+		return status.Error(codes.ResourceExhausted, usecase.ErrTooManyWaiters.Error())
+	case usecase.KindCanceled:
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,102 @@
+package grpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pb/goboard.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/grpc/pb"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	pb.UnimplementedGoBoardServer
+
+	server  *grpc.Server
+	addr    string
+	usecase usecase.Usecaser
+	log     logger.Logger
+
+	// shutdown is closed by Shutdown to end the subscriptions, which would
+	// otherwise keep a graceful stop waiting.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+type Config struct {
+	Addr string
+	Log  logger.Logger
+	// MaxRecvSize caps request messages in bytes, zero keeps the gRPC default.
+	MaxRecvSize int
+}
+
+func NewServer(cfg Config, usecase usecase.Usecaser) *Server {
+	s := &Server{
+		addr:     cfg.Addr,
+		usecase:  usecase,
+		log:      cfg.Log,
+		shutdown: make(chan struct{}),
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unaryAccessLog),
+		grpc.ChainStreamInterceptor(s.streamAccessLog),
+	}
+
+	if cfg.MaxRecvSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.MaxRecvSize))
+	}
+
+	s.server = grpc.NewServer(opts...)
+	pb.RegisterGoBoardServer(s.server, s)
+
+	return s
+}
+
+// Serve listens until the server fails or Shutdown is called, returning nil in the latter case.
+func (s *Server) Serve() error {
+	l, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("serve grpc: listen: %w", err)
+	}
+
+	return s.ServeListener(l)
+}
+
+// ServeListener is Serve on a listener opened by the caller, which the server closes.
+func (s *Server) ServeListener(l net.Listener) error {
+	if err := s.server.Serve(l); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("serve grpc: %w", err)
+	}
+
+	return nil
+}
+
+// Shutdown ends the subscriptions, stops accepting connections and waits
+// for in-flight calls until ctx is done, then closes the connections.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+
+		return fmt.Errorf("shutdown grpc: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,213 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/grpc"
+	"github.com/Batyachelly/goBoard/internal/transport/grpc/pb"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// serve starts a server on a loopback port with the memory backend and
+// returns a client connected to it.
+func serve(t *testing.T, uc usecase.Usecaser) (pb.GoBoardClient, *grpc.Server) {
+	t.Helper()
+
+	s := grpc.NewServer(grpc.Config{Log: logger.TestLogger{}}, uc)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.ServeListener(l)
+	}()
+
+	conn, err := grpclib.Dial(l.Addr().String(), grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, s.Shutdown(ctx))
+		require.NoError(t, <-serveErr)
+	})
+
+	return pb.NewGoBoardClient(conn), s
+}
+
+func newUsecase() *usecase.Usecase {
+	return usecase.NewUsecase(memory.NewDatabaseService(memory.Config{Boards: []string{"General", "Random"}}), usecase.Config{
+		DeletionWindow: time.Hour,
+	})
+}
+
+func TestServer_Threads(t *testing.T) {
+	t.Parallel()
+
+	client, _ := serve(t, newUsecase())
+	ctx := context.Background()
+
+	boards, err := client.ListBoards(ctx, &pb.ListBoardsRequest{})
+	require.NoError(t, err)
+	require.Len(t, boards.GetBoards(), 2)
+	require.Equal(t, "General", boards.GetBoards()[0].GetTitle())
+
+	thread, err := client.PostThread(ctx, &pb.PostThreadRequest{BoardId: 1, Title: "Title", Text: "Text"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), thread.GetThreadId())
+	require.NotEmpty(t, thread.GetDeletionToken(), "a token is issued without a password")
+
+	var header metadata.MD
+
+	reply, err := client.PostMessage(ctx, &pb.PostMessageRequest{
+		BoardId:  1,
+		ThreadId: thread.GetThreadId(),
+		Text:     "Reply",
+		Password: "secret",
+		Sage:     true,
+	}, grpclib.Header(&header))
+	require.NoError(t, err)
+	require.Empty(t, reply.GetDeletionToken())
+	require.Len(t, header.Get(grpc.RequestIDMetadata), 1)
+
+	board, err := client.GetBoard(ctx, &pb.GetBoardRequest{BoardId: 1})
+	require.NoError(t, err)
+	require.Equal(t, "General", board.GetTitle())
+	require.NotEmpty(t, board.GetThreads())
+
+	messages, err := client.GetThread(ctx, &pb.GetThreadRequest{BoardId: 1, ThreadId: thread.GetThreadId()})
+	require.NoError(t, err)
+	require.Len(t, messages.GetMessages(), 2)
+	require.WithinDuration(t, time.Now(), messages.GetMessages()[1].GetCreated().AsTime(), time.Minute)
+
+	messages, err = client.GetThread(ctx, &pb.GetThreadRequest{
+		BoardId:  1,
+		ThreadId: thread.GetThreadId(),
+		Since:    messages.GetMessages()[0].GetId(),
+	})
+	require.NoError(t, err)
+	require.Len(t, messages.GetMessages(), 1)
+	require.Equal(t, reply.GetMessageId(), messages.GetMessages()[0].GetId())
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Parallel()
+
+	uc := newUsecase()
+	client, _ := serve(t, uc)
+
+	_, err := uc.PostThread(context.Background(), &models.Message{BoardID: 2, Title: "Title", Text: "Text"})
+	require.NoError(t, err)
+
+	_, err = uc.PostBan(context.Background(), &models.Ban{BoardID: 2, Network: "127.0.0.1", Reason: "spam"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		call     func(ctx context.Context) error
+		wantCode codes.Code
+	}{
+		{
+			name: "unknown board",
+			call: func(ctx context.Context) error {
+				_, err := client.GetBoard(ctx, &pb.GetBoardRequest{BoardId: 9})
+
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "thread on unknown board",
+			call: func(ctx context.Context) error {
+				_, err := client.PostThread(ctx, &pb.PostThreadRequest{BoardId: 9, Title: "Title", Text: "Text"})
+
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "banned",
+			call: func(ctx context.Context) error {
+				_, err := client.PostMessage(ctx, &pb.PostMessageRequest{BoardId: 2, ThreadId: 1, Text: "Reply"})
+
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "banned on another board",
+			call: func(ctx context.Context) error {
+				_, err := client.PostThread(ctx, &pb.PostThreadRequest{BoardId: 1, Title: "Title", Text: "Text"})
+
+				return err
+			},
+			wantCode: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.call(context.Background())
+			require.Equal(t, tt.wantCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestServer_SubscribeThread(t *testing.T) {
+	t.Parallel()
+
+	uc := newUsecase()
+	client, s := serve(t, uc)
+
+	threadID, err := uc.PostThread(context.Background(), &models.Message{BoardID: 1, Title: "Title", Text: "Text"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.SubscribeThread(ctx, &pb.SubscribeThreadRequest{BoardId: 1, ThreadId: threadID})
+	require.NoError(t, err)
+
+	// The messages already posted come first.
+	m, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "Title", m.GetTitle())
+
+	reply, err := client.PostMessage(ctx, &pb.PostMessageRequest{BoardId: 1, ThreadId: threadID, Text: "Reply"})
+	require.NoError(t, err)
+
+	m, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, reply.GetMessageId(), m.GetId())
+	require.Equal(t, "Reply", m.GetText())
+
+	// Shutting down ends the subscription instead of waiting for it.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Second)
+	defer shutdownCancel()
+
+	require.NoError(t, s.Shutdown(shutdownCtx))
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err), err)
+}
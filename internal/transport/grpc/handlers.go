@@ -0,0 +1,220 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/transport/grpc/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// subscribeWait is how long a subscription waits for new messages before
+// asking the usecase again. It only bounds a single long-poll, the stream
+// goes on until the client or the server ends it.
+const subscribeWait = time.Minute
+
+func (s *Server) ListBoards(ctx context.Context, _ *pb.ListBoardsRequest) (*pb.ListBoardsResponse, error) {
+	modelBoards, err := s.usecase.GetBoardList(ctx)
+	if err != nil {
+		s.log.WithContext(ctx).Error("ListBoards", "error", err)
+
+		return nil, errorStatus(err)
+	}
+
+	resp := &pb.ListBoardsResponse{Boards: make([]*pb.Board, 0, len(modelBoards))}
+
+	for _, modelBoard := range modelBoards {
+		resp.Boards = append(resp.Boards, &pb.Board{
+			Id:    modelBoard.ID,
+			Title: modelBoard.Title,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *Server) GetBoard(ctx context.Context, req *pb.GetBoardRequest) (*pb.Board, error) {
+	modelBoard, err := s.usecase.GetBoard(ctx, req.GetBoardId())
+	if err != nil {
+		s.log.WithContext(ctx).Error("GetBoard", "error", err)
+
+		return nil, errorStatus(err)
+	}
+
+	return &pb.Board{
+		Id:      modelBoard.ID,
+		Title:   modelBoard.Title,
+		Threads: messages(modelBoard.Threads),
+	}, nil
+}
+
+func (s *Server) GetThread(ctx context.Context, req *pb.GetThreadRequest) (*pb.GetThreadResponse, error) {
+	filter := models.ThreadFilter{
+		PosterID: req.GetPosterId(),
+		Since:    req.GetSince(),
+	}
+
+	modelMessages, err := s.usecase.GetThread(ctx, req.GetBoardId(), req.GetThreadId(), filter)
+	if err != nil {
+		s.log.WithContext(ctx).Error("GetThread", "error", err)
+
+		return nil, errorStatus(err)
+	}
+
+	return &pb.GetThreadResponse{Messages: messages(modelMessages)}, nil
+}
+
+func (s *Server) PostThread(ctx context.Context, req *pb.PostThreadRequest) (*pb.PostThreadResponse, error) {
+	if err := s.banCheck(ctx, req.GetBoardId()); err != nil {
+		return nil, err
+	}
+
+	message := &models.Message{
+		BoardID:          req.GetBoardId(),
+		Title:            req.GetTitle(),
+		Text:             req.GetText(),
+		Content:          req.GetContent(),
+		IP:               clientIP(ctx),
+		DeletionPassword: req.GetPassword(),
+	}
+
+	threadID, err := s.usecase.PostThread(ctx, message)
+	if err != nil {
+		s.log.WithContext(ctx).Error("PostThread", "error", err)
+
+		return nil, errorStatus(err)
+	}
+
+	resp := &pb.PostThreadResponse{ThreadId: threadID}
+
+	if req.GetPassword() == "" {
+		resp.DeletionToken = message.DeletionPassword
+	}
+
+	return resp, nil
+}
+
+func (s *Server) PostMessage(ctx context.Context, req *pb.PostMessageRequest) (*pb.PostMessageResponse, error) {
+	if err := s.banCheck(ctx, req.GetBoardId()); err != nil {
+		return nil, err
+	}
+
+	message := &models.Message{
+		BoardID:          req.GetBoardId(),
+		ThreadID:         req.GetThreadId(),
+		Title:            req.GetTitle(),
+		Text:             req.GetText(),
+		Content:          req.GetContent(),
+		IP:               clientIP(ctx),
+		DeletionPassword: req.GetPassword(),
+		Sage:             req.GetSage(),
+	}
+
+	messageID, err := s.usecase.PostMessage(ctx, message)
+	if err != nil {
+		s.log.WithContext(ctx).Error("PostMessage", "error", err)
+
+		return nil, errorStatus(err)
+	}
+
+	resp := &pb.PostMessageResponse{MessageId: messageID}
+
+	if req.GetPassword() == "" {
+		resp.DeletionToken = message.DeletionPassword
+	}
+
+	return resp, nil
+}
+
+// SubscribeThread long-polls the thread through the usecase, sending every
+// message after the last one sent. The server shutting down ends the stream
+// with Unavailable, so clients know to reconnect with the last ID as since.
+func (s *Server) SubscribeThread(req *pb.SubscribeThreadRequest, stream pb.GoBoard_SubscribeThreadServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	since := req.GetSince()
+
+	for {
+		modelMessages, err := s.usecase.GetThread(ctx, req.GetBoardId(), req.GetThreadId(), models.ThreadFilter{
+			Since: since,
+			Wait:  subscribeWait,
+		})
+		if err != nil && ctx.Err() == nil {
+			s.log.WithContext(ctx).Error("SubscribeThread", "error", err)
+
+			return errorStatus(err)
+		}
+
+		for _, modelMessage := range modelMessages {
+			if err := stream.Send(message(modelMessage)); err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			if modelMessage.ID > since {
+				since = modelMessage.ID
+			}
+		}
+
+		select {
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		default:
+		}
+
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+	}
+}
+
+// banCheck rejects calls from banned addresses, like the BanCheck
+// middleware of the HTTP API.
+func (s *Server) banCheck(ctx context.Context, boardID uint64) error {
+	bans, err := s.usecase.GetActiveBans(ctx, clientIP(ctx), boardID)
+	if err != nil {
+		s.log.WithContext(ctx).Error("check ban", "error", err)
+
+		return errorStatus(err)
+	}
+
+	if len(bans) == 0 {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "banned, ban #%d: %s", bans[0].ID, bans[0].Reason)
+}
+
+func message(m models.Message) *pb.Message {
+	return &pb.Message{
+		Id:       m.ID,
+		Title:    m.Title,
+		Text:     m.Text,
+		Content:  m.Content,
+		Created:  timestamppb.New(m.Created),
+		PosterId: m.PosterID,
+		Sage:     m.Sage,
+	}
+}
+
+func messages(list models.MessageList) []*pb.Message {
+	resp := make([]*pb.Message, 0, len(list))
+
+	for _, m := range list {
+		resp = append(resp, message(m))
+	}
+
+	return resp
+}
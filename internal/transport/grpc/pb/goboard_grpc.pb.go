@@ -0,0 +1,326 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pb/goboard.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	GoBoard_ListBoards_FullMethodName      = "/goboard.v1.GoBoard/ListBoards"
+	GoBoard_GetBoard_FullMethodName        = "/goboard.v1.GoBoard/GetBoard"
+	GoBoard_GetThread_FullMethodName       = "/goboard.v1.GoBoard/GetThread"
+	GoBoard_PostThread_FullMethodName      = "/goboard.v1.GoBoard/PostThread"
+	GoBoard_PostMessage_FullMethodName     = "/goboard.v1.GoBoard/PostMessage"
+	GoBoard_SubscribeThread_FullMethodName = "/goboard.v1.GoBoard/SubscribeThread"
+)
+
+// GoBoardClient is the client API for GoBoard service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GoBoardClient interface {
+	ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (*ListBoardsResponse, error)
+	GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*Board, error)
+	GetThread(ctx context.Context, in *GetThreadRequest, opts ...grpc.CallOption) (*GetThreadResponse, error)
+	PostThread(ctx context.Context, in *PostThreadRequest, opts ...grpc.CallOption) (*PostThreadResponse, error)
+	PostMessage(ctx context.Context, in *PostMessageRequest, opts ...grpc.CallOption) (*PostMessageResponse, error)
+	// SubscribeThread streams the messages of a thread after since, first
+	// those already posted, then new ones as they are posted.
+	SubscribeThread(ctx context.Context, in *SubscribeThreadRequest, opts ...grpc.CallOption) (GoBoard_SubscribeThreadClient, error)
+}
+
+type goBoardClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGoBoardClient(cc grpc.ClientConnInterface) GoBoardClient {
+	return &goBoardClient{cc}
+}
+
+func (c *goBoardClient) ListBoards(ctx context.Context, in *ListBoardsRequest, opts ...grpc.CallOption) (*ListBoardsResponse, error) {
+	out := new(ListBoardsResponse)
+	err := c.cc.Invoke(ctx, GoBoard_ListBoards_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBoardClient) GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*Board, error) {
+	out := new(Board)
+	err := c.cc.Invoke(ctx, GoBoard_GetBoard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBoardClient) GetThread(ctx context.Context, in *GetThreadRequest, opts ...grpc.CallOption) (*GetThreadResponse, error) {
+	out := new(GetThreadResponse)
+	err := c.cc.Invoke(ctx, GoBoard_GetThread_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBoardClient) PostThread(ctx context.Context, in *PostThreadRequest, opts ...grpc.CallOption) (*PostThreadResponse, error) {
+	out := new(PostThreadResponse)
+	err := c.cc.Invoke(ctx, GoBoard_PostThread_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBoardClient) PostMessage(ctx context.Context, in *PostMessageRequest, opts ...grpc.CallOption) (*PostMessageResponse, error) {
+	out := new(PostMessageResponse)
+	err := c.cc.Invoke(ctx, GoBoard_PostMessage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *goBoardClient) SubscribeThread(ctx context.Context, in *SubscribeThreadRequest, opts ...grpc.CallOption) (GoBoard_SubscribeThreadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GoBoard_ServiceDesc.Streams[0], GoBoard_SubscribeThread_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &goBoardSubscribeThreadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GoBoard_SubscribeThreadClient interface {
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type goBoardSubscribeThreadClient struct {
+	grpc.ClientStream
+}
+
+func (x *goBoardSubscribeThreadClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GoBoardServer is the server API for GoBoard service.
+// All implementations must embed UnimplementedGoBoardServer
+// for forward compatibility
+type GoBoardServer interface {
+	ListBoards(context.Context, *ListBoardsRequest) (*ListBoardsResponse, error)
+	GetBoard(context.Context, *GetBoardRequest) (*Board, error)
+	GetThread(context.Context, *GetThreadRequest) (*GetThreadResponse, error)
+	PostThread(context.Context, *PostThreadRequest) (*PostThreadResponse, error)
+	PostMessage(context.Context, *PostMessageRequest) (*PostMessageResponse, error)
+	// SubscribeThread streams the messages of a thread after since, first
+	// those already posted, then new ones as they are posted.
+	SubscribeThread(*SubscribeThreadRequest, GoBoard_SubscribeThreadServer) error
+	mustEmbedUnimplementedGoBoardServer()
+}
+
+// UnimplementedGoBoardServer must be embedded to have forward compatible implementations.
+type UnimplementedGoBoardServer struct {
+}
+
+func (UnimplementedGoBoardServer) ListBoards(context.Context, *ListBoardsRequest) (*ListBoardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBoards not implemented")
+}
+func (UnimplementedGoBoardServer) GetBoard(context.Context, *GetBoardRequest) (*Board, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBoard not implemented")
+}
+func (UnimplementedGoBoardServer) GetThread(context.Context, *GetThreadRequest) (*GetThreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThread not implemented")
+}
+func (UnimplementedGoBoardServer) PostThread(context.Context, *PostThreadRequest) (*PostThreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostThread not implemented")
+}
+func (UnimplementedGoBoardServer) PostMessage(context.Context, *PostMessageRequest) (*PostMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostMessage not implemented")
+}
+func (UnimplementedGoBoardServer) SubscribeThread(*SubscribeThreadRequest, GoBoard_SubscribeThreadServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeThread not implemented")
+}
+func (UnimplementedGoBoardServer) mustEmbedUnimplementedGoBoardServer() {}
+
+// UnsafeGoBoardServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GoBoardServer will
+// result in compilation errors.
+type UnsafeGoBoardServer interface {
+	mustEmbedUnimplementedGoBoardServer()
+}
+
+func RegisterGoBoardServer(s grpc.ServiceRegistrar, srv GoBoardServer) {
+	s.RegisterService(&GoBoard_ServiceDesc, srv)
+}
+
+func _GoBoard_ListBoards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBoardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBoardServer).ListBoards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBoard_ListBoards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBoardServer).ListBoards(ctx, req.(*ListBoardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBoard_GetBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBoardServer).GetBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBoard_GetBoard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBoardServer).GetBoard(ctx, req.(*GetBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBoard_GetThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBoardServer).GetThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBoard_GetThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBoardServer).GetThread(ctx, req.(*GetThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBoard_PostThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBoardServer).PostThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBoard_PostThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBoardServer).PostThread(ctx, req.(*PostThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBoard_PostMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GoBoardServer).PostMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GoBoard_PostMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GoBoardServer).PostMessage(ctx, req.(*PostMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GoBoard_SubscribeThread_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeThreadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GoBoardServer).SubscribeThread(m, &goBoardSubscribeThreadServer{stream})
+}
+
+type GoBoard_SubscribeThreadServer interface {
+	Send(*Message) error
+	grpc.ServerStream
+}
+
+type goBoardSubscribeThreadServer struct {
+	grpc.ServerStream
+}
+
+func (x *goBoardSubscribeThreadServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// GoBoard_ServiceDesc is the grpc.ServiceDesc for GoBoard service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GoBoard_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goboard.v1.GoBoard",
+	HandlerType: (*GoBoardServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBoards",
+			Handler:    _GoBoard_ListBoards_Handler,
+		},
+		{
+			MethodName: "GetBoard",
+			Handler:    _GoBoard_GetBoard_Handler,
+		},
+		{
+			MethodName: "GetThread",
+			Handler:    _GoBoard_GetThread_Handler,
+		},
+		{
+			MethodName: "PostThread",
+			Handler:    _GoBoard_PostThread_Handler,
+		},
+		{
+			MethodName: "PostMessage",
+			Handler:    _GoBoard_PostMessage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeThread",
+			Handler:       _GoBoard_SubscribeThread_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pb/goboard.proto",
+}
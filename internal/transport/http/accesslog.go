@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/response"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// traceIDKey holds where traceLog leaves the trace ID for AccessLog.
+type traceIDKey struct{}
+
+// AccessLog assigns every request an ID, reusing a sane incoming
+// X-Request-ID, returns it in the response and logs the request once done.
+// The ID is added to the context fields of the logger. It wraps the router,
+// so requests no route matches are logged too, and their trace ID, when
+// there is one, comes from traceLog inside the router.
+func AccessLog(log logger.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := logger.RequestID(r.Header.Get(RequestIDHeader))
+
+			w.Header().Set(RequestIDHeader, requestID)
+
+			var traceID string
+
+			ctx := logger.ContextWithRequestID(r.Context(), requestID)
+			ctx = context.WithValue(ctx, traceIDKey{}, &traceID)
+
+			rec := response.NewRecorder(w)
+			start := time.Now()
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			l := log.WithContext(ctx)
+			if traceID != "" {
+				l = l.With("trace_id", traceID)
+			}
+
+			l.Info("request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.Status(),
+				"bytes", rec.Bytes(),
+				"duration_ms", float64(time.Since(start))/float64(time.Millisecond),
+				"remote", ClientIP(r),
+			)
+		})
+	}
+}
+
+// traceLog adds the trace ID of the request span to the context fields of
+// the logger and hands it to AccessLog, which runs before the span starts.
+func traceLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			if traceID, ok := ctx.Value(traceIDKey{}).(*string); ok {
+				*traceID = sc.TraceID().String()
+			}
+
+			r = r.WithContext(logger.ContextWithFields(ctx, "trace_id", sc.TraceID().String()))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestLog returns the server logger with the fields of the request context.
+func (s *Server) requestLog(r *http.Request) logger.Logger {
+	return s.log.WithContext(r.Context())
+}
@@ -0,0 +1,205 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/tracing"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	logger.TestLogger
+	fields []interface{}
+	info   []interface{}
+}
+
+func (l *recordingLogger) WithContext(ctx context.Context) logger.Logger {
+	l.fields = logger.FieldsFromContext(ctx)
+
+	return l
+}
+
+func (l *recordingLogger) With(keysAndValues ...interface{}) logger.Logger {
+	l.fields = append(l.fields, keysAndValues...)
+
+	return l
+}
+
+func (l *recordingLogger) Info(_ string, keysAndValues ...interface{}) {
+	l.info = keysAndValues
+}
+
+// field returns the value logged for the key.
+func field(keysAndValues []interface{}, key string) interface{} {
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if keysAndValues[i] == key {
+			return keysAndValues[i+1]
+		}
+	}
+
+	return nil
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		requestID string
+		reused    bool
+	}{
+		{
+			name:      "incoming id",
+			requestID: "edge-42.a_b",
+			reused:    true,
+		},
+		{
+			name:      "invalid incoming id",
+			requestID: "bad id\n",
+		},
+		{
+			name: "no incoming id",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			log := &recordingLogger{}
+
+			var handlerID string
+
+			h := http.AccessLog(log)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				handlerID = logger.RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				req.Header.Set(http.RequestIDHeader, tt.requestID)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			got := w.Header().Get(http.RequestIDHeader)
+			require.NotEmpty(t, got)
+			require.Equal(t, got, handlerID)
+			require.Equal(t, []interface{}{"request_id", got}, log.fields)
+
+			if tt.reused {
+				require.Equal(t, tt.requestID, got)
+			} else {
+				require.Len(t, got, 32)
+			}
+		})
+	}
+}
+
+func TestServer_AccessLog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "unknown path",
+			method:     nethttp.MethodGet,
+			path:       "/unknown",
+			wantStatus: nethttp.StatusNotFound,
+		},
+		{
+			name:       "unknown method",
+			method:     nethttp.MethodPost,
+			path:       "/healthz",
+			wantStatus: nethttp.StatusMethodNotAllowed,
+		},
+		{
+			name:   "preflight",
+			method: nethttp.MethodOptions,
+			path:   "/api/v1/board",
+			header: map[string]string{
+				"Origin":                        "https://frontend.example.com",
+				"Access-Control-Request-Method": nethttp.MethodPost,
+			},
+			wantStatus: nethttp.StatusNoContent,
+		},
+		{
+			name:       "routed",
+			method:     nethttp.MethodGet,
+			path:       "/healthz",
+			wantStatus: nethttp.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			log := &recordingLogger{}
+
+			s := http.NewServer(http.Config{
+				Log:            log,
+				CORS:           http.CORSConfig{Origins: []string{"https://frontend.example.com"}, Methods: []string{nethttp.MethodPost}},
+				TrustedProxies: []string{"192.0.2.1"},
+			}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			s.Handler().ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			requestID := w.Header().Get(http.RequestIDHeader)
+			require.NotEmpty(t, requestID)
+			require.Equal(t, []interface{}{"request_id", requestID}, log.fields)
+
+			require.Equal(t, tt.wantStatus, field(log.info, "status"))
+			require.Equal(t, tt.path, field(log.info, "path"))
+			require.Equal(t, "203.0.113.7", field(log.info, "remote"), "the address resolved by RealIP")
+		})
+	}
+}
+
+func TestServer_AccessLog_TraceID(t *testing.T) {
+	t.Parallel()
+
+	tracer := tracing.NewTracer(tracing.Config{Exporter: tracing.NewJSONExporter(io.Discard)})
+	t.Cleanup(func() { require.NoError(t, tracer.Shutdown(context.Background())) })
+
+	log := &recordingLogger{}
+
+	s := http.NewServer(http.Config{
+		Log:    log,
+		Tracer: tracer,
+	}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, "/healthz", nil))
+
+	require.Equal(t, nethttp.StatusOK, w.Code)
+	require.Len(t, log.fields, 4)
+	require.Equal(t, "trace_id", log.fields[2])
+	require.Len(t, log.fields[3], 32)
+}
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/pkg/data"
+
+	"github.com/gorilla/mux"
+)
+
+var banPage = template.Must(template.New("ban").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>You are banned</title></head>
+<body>
+<h1>You are banned{{if .BoardID}} from this board{{end}}</h1>
+<p>Reason: {{.Reason}}</p>
+{{if .Expires}}<p>Your ban expires {{.Expires.UTC.Format "2006-01-02 15:04 MST"}}.</p>{{else}}<p>Your ban is permanent.</p>{{end}}
+<p>Ban #{{.BanID}}</p>
+</body>
+</html>
+`))
+
+// BanCheck rejects requests from banned addresses, answering with a ban page
+// for browsers and a JSON explanation otherwise.
+func (s *Server) BanCheck(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		boardID, _ := strconv.ParseUint(mux.Vars(r)["board_id"], 10, 64)
+
+		bans, err := s.usecase.GetActiveBans(r.Context(), ClientIP(r), boardID)
+		if err != nil {
+			s.requestLog(r).Error("check ban", "error", err)
+
+			s.responseJSON(w, http.StatusInternalServerError, nil)
+
+			return
+		}
+
+		if len(bans) == 0 {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ban := bans[0]
+		resp := &data.BannedResponse{
+			BanID:   ban.ID,
+			BoardID: ban.BoardID,
+			Reason:  ban.Reason,
+			Expires: ban.Expires,
+		}
+
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusForbidden)
+
+			if err := banPage.Execute(w, resp); err != nil {
+				s.requestLog(r).Error("render ban page", "error", err)
+			}
+
+			return
+		}
+
+		s.responseJSON(w, http.StatusForbidden, resp)
+	})
+}
+
+// Get bans
+// @Summary      Get bans
+// @Description  Get active bans
+// @Tags         moderation
+// @Produce      json
+// @Success      200  {object}  data.GetBansResponse
+// @Security     ModeratorToken
+// @Router       /mod/ban [get]
+func (s *Server) GetBans(w http.ResponseWriter, r *http.Request) {
+	modelBans, err := s.usecase.GetBanList(r.Context())
+	if err != nil {
+		s.requestLog(r).Error("GET bans", "error", err)
+
+		s.responseJSON(w, http.StatusInternalServerError, nil)
+
+		return
+	}
+
+	respBans := make(data.GetBansResponse, 0, len(modelBans))
+
+	for _, ban := range modelBans {
+		respBans = append(respBans, data.Ban{
+			ID:        ban.ID,
+			BoardID:   ban.BoardID,
+			Network:   ban.Network,
+			IPHash:    ban.IPHash,
+			Reason:    ban.Reason,
+			Moderator: ban.Moderator,
+			Created:   ban.Created,
+			Expires:   ban.Expires,
+		})
+	}
+
+	s.responseJSON(w, http.StatusOK, respBans)
+}
+
+// Post ban
+// @Summary      Ban network
+// @Description  Ban an IP address, CIDR range or address hash globally or on a single board
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        ban body data.PostBanRequest true "Ban request"
+// @Success      200  {object}  data.PostBanResponse
+// @Failure      400,413,415  {object}  data.ErrorResponse
+// @Security     ModeratorToken
+// @Router       /mod/ban [post]
+func (s *Server) PostBan(w http.ResponseWriter, r *http.Request) {
+	req := new(data.PostBanRequest)
+
+	if !decodeJSON(w, r, req) {
+		return
+	}
+
+	duration, err := parseBanDuration(req.Duration)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	banID, err := s.usecase.PostBan(r.Context(), &models.Ban{
+		BoardID:   req.BoardID,
+		Network:   req.Network,
+		IPHash:    req.IPHash,
+		Reason:    req.Reason,
+		Moderator: Moderator(r.Context()),
+		Expires:   banExpires(duration),
+	})
+	if err != nil {
+		s.requestLog(r).Error("POST ban", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.responseJSON(w, http.StatusOK, &data.PostBanResponse{BanID: banID})
+}
+
+// Post message ban
+// @Summary      Ban message author
+// @Description  Ban the address a message was posted from, on its board or globally
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        board_id    path int  true  "board ID"
+// @Param        message_id  path int  true  "message ID"
+// @Param        ban body data.PostMessageBanRequest true "Ban request"
+// @Success      200  {object}  data.PostBanResponse
+// @Failure      404,422
+// @Failure      400,413,415  {object}  data.ErrorResponse
+// @Security     ModeratorToken
+// @Router       /mod/board/{board_id}/message/{message_id}/ban [post]
+func (s *Server) PostMessageBan(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	messageID, err := strconv.ParseUint(vars["message_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	req := new(data.PostMessageBanRequest)
+
+	if !decodeJSON(w, r, req) {
+		return
+	}
+
+	duration, err := parseBanDuration(req.Duration)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	ban := &models.Ban{
+		BoardID:   boardID,
+		Reason:    req.Reason,
+		Moderator: Moderator(r.Context()),
+		Expires:   banExpires(duration),
+	}
+
+	if req.Global {
+		ban.BoardID = 0
+	}
+
+	banID, err := s.usecase.BanMessageAuthor(r.Context(), boardID, messageID, ban)
+	if err != nil {
+		s.requestLog(r).Error("POST message ban", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.responseJSON(w, http.StatusOK, &data.PostBanResponse{BanID: banID})
+}
+
+// Get message author
+// @Summary      Get message author
+// @Description  Reveal the address a message was posted from, the address is omitted once purged
+// @Tags         moderation
+// @Produce      json
+// @Param        board_id    path int  true  "board ID"
+// @Param        message_id  path int  true  "message ID"
+// @Success      200  {object}  data.MessageAuthorResponse
+// @Failure      404,422
+// @Security     ModeratorToken
+// @Router       /mod/board/{board_id}/message/{message_id}/author [get]
+func (s *Server) GetMessageAuthor(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	messageID, err := strconv.ParseUint(vars["message_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	author, err := s.usecase.GetMessageAuthor(r.Context(), boardID, messageID)
+	if err != nil {
+		s.requestLog(r).Error("GET message author", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.requestLog(r).Info("moderator revealed message author", "moderator", Moderator(r.Context()), "board_id", boardID, "message_id", messageID)
+
+	s.responseJSON(w, http.StatusOK, &data.MessageAuthorResponse{
+		IP:     author.IP,
+		IPHash: author.IPHash,
+	})
+}
+
+// Delete ban
+// @Summary      Lift ban
+// @Description  Lift ban by ID
+// @Tags         moderation
+// @Param        ban_id  path int  true  "ban ID"
+// @Success      200
+// @Failure      404
+// @Security     ModeratorToken
+// @Router       /mod/ban/{ban_id} [delete]
+func (s *Server) DeleteBan(w http.ResponseWriter, r *http.Request) {
+	banID, err := strconv.ParseUint(mux.Vars(r)["ban_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	if err := s.usecase.DeleteBan(r.Context(), banID); err != nil {
+		s.requestLog(r).Error("DELETE ban", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.responseJSON(w, http.StatusOK, nil)
+}
+
+// parseBanDuration parses a ban duration, an empty duration meaning
+// a permanent ban.
+func parseBanDuration(duration string) (time.Duration, error) {
+	if duration == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	if d <= 0 {
+		return 0, errors.New("non-positive ban duration")
+	}
+
+	return d, nil
+}
+
+func banExpires(d time.Duration) *time.Time {
+	if d == 0 {
+		return nil
+	}
+
+	expires := time.Now().UTC().Add(d)
+
+	return &expires
+}
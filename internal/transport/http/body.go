@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Batyachelly/goBoard/pkg/data"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	errCodeBodyTooLarge    = "body_too_large"
+	errCodeUnsupportedType = "unsupported_media_type"
+	errCodeInvalidJSON     = "invalid_json"
+)
+
+// maxBytesMessage is the error of http.MaxBytesReader, http.MaxBytesError
+// is too new to match it by type.
+const maxBytesMessage = "http: request body too large"
+
+var errTrailingData = errors.New("unexpected data after the JSON value")
+
+// MaxBodySize caps request bodies at limit bytes, rejecting larger declared
+// lengths up front and making longer reads fail.
+func MaxBodySize(limit int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				responseError(w, http.StatusRequestEntityTooLarge, errCodeBodyTooLarge,
+					"request body exceeds "+strconv.FormatInt(limit, 10)+" bytes")
+
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// decodeJSON reads the JSON request body into v, rejecting other content
+// types, unknown fields and anything after the value. On failure it writes
+// the error response and returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		responseError(w, http.StatusUnsupportedMediaType, errCodeUnsupportedType, "Content-Type must be application/json")
+
+		return false
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(v)
+	if err == nil {
+		// Only the end of the body may follow the value.
+		if _, err = dec.Token(); errors.Is(err, io.EOF) {
+			return true
+		}
+
+		if err == nil {
+			err = errTrailingData
+		}
+	}
+
+	if err.Error() == maxBytesMessage {
+		responseError(w, http.StatusRequestEntityTooLarge, errCodeBodyTooLarge, "request body is too large")
+
+		return false
+	}
+
+	responseError(w, http.StatusBadRequest, errCodeInvalidJSON, "invalid request body: "+err.Error())
+
+	return false
+}
+
+func responseError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(&data.ErrorResponse{Code: code, Message: message}) //nolint:errcheck
+}
@@ -0,0 +1,136 @@
+package http_test
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+	"github.com/Batyachelly/goBoard/pkg/data"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_PostThread_Body(t *testing.T) {
+	t.Parallel()
+
+	const limit = 64
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		// chunked hides the length, so only reading finds the body too large.
+		chunked  bool
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:        "valid",
+			contentType: "application/json",
+			body:        `{"title":"Title","text":"Text"}`,
+			wantCode:    nethttp.StatusOK,
+		},
+		{
+			name:        "charset",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"text":"Text"}`,
+			wantCode:    nethttp.StatusOK,
+		},
+		{
+			name:        "unknown field",
+			contentType: "application/json",
+			body:        `{"threadID":1,"text":"Text"}`,
+			wantCode:    nethttp.StatusBadRequest,
+			wantErr:     "invalid_json",
+		},
+		{
+			name:        "trailing data",
+			contentType: "application/json",
+			body:        `{"text":"Text"}{"text":"Text"}`,
+			wantCode:    nethttp.StatusBadRequest,
+			wantErr:     "invalid_json",
+		},
+		{
+			name:        "malformed",
+			contentType: "application/json",
+			body:        `{"text":`,
+			wantCode:    nethttp.StatusBadRequest,
+			wantErr:     "invalid_json",
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        `text=Text`,
+			wantCode:    nethttp.StatusUnsupportedMediaType,
+			wantErr:     "unsupported_media_type",
+		},
+		{
+			name:     "no content type",
+			body:     `{"text":"Text"}`,
+			wantCode: nethttp.StatusUnsupportedMediaType,
+			wantErr:  "unsupported_media_type",
+		},
+		{
+			name:        "too large",
+			contentType: "application/json",
+			body:        `{"text":"` + strings.Repeat("a", limit) + `"}`,
+			wantCode:    nethttp.StatusRequestEntityTooLarge,
+			wantErr:     "body_too_large",
+		},
+		{
+			name:        "too large without length",
+			contentType: "application/json",
+			body:        `{"text":"` + strings.Repeat("a", limit) + `"}`,
+			chunked:     true,
+			wantCode:    nethttp.StatusRequestEntityTooLarge,
+			wantErr:     "body_too_large",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+			s := http.NewServer(http.Config{
+				Log: logger.TestLogger{},
+			}, usecase.NewUsecase(ds, usecase.Config{}))
+
+			h := http.MaxBodySize(limit)(nethttp.HandlerFunc(s.PostThread))
+
+			req := httptest.NewRequest(nethttp.MethodPost, "/board/1/thread", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"board_id": "1"})
+
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+
+			if tt.wantErr == "" {
+				return
+			}
+
+			var resp data.ErrorResponse
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, tt.wantErr, resp.Code)
+			require.NotEmpty(t, resp.Message)
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package http
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gorilla/mux"
+)
+
+// Encodings in the order preferred when a client accepts several equally.
+var encodings = []string{"br", "gzip", "deflate"}
+
+// brotliLevel trades some ratio for the speed needed on every response.
+const brotliLevel = 4
+
+// encoder is the part shared by the gzip, zlib and brotli writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	"br": {New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}},
+	"gzip": {New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}},
+	// HTTP deflate is the zlib format, not raw deflate.
+	"deflate": {New: func() interface{} {
+		return zlib.NewWriter(nil)
+	}},
+}
+
+// Compress encodes responses of at least minSize bytes with the encoding
+// the client prefers among brotli, gzip and deflate. Already compressed
+// media, event streams and responses flushed before reaching minSize are
+// sent as they are.
+func Compress(minSize int) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks the accepted encoding with the highest quality,
+// "" when the client accepts none of them.
+func negotiateEncoding(accept string) string {
+	if accept == "" {
+		return ""
+	}
+
+	quality := make(map[string]float64)
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				v, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					v = 0
+				}
+
+				q = v
+			}
+		}
+
+		quality[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+
+	for _, encoding := range encodings {
+		q, ok := quality[encoding]
+		if !ok {
+			q = quality["*"]
+		}
+
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best
+}
+
+// compressible tells whether compressing a body of the content type is
+// worth it and does not break streaming.
+func compressible(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+
+	switch {
+	case mediaType == "image/svg+xml":
+		return true
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "font/woff"):
+		return false
+	}
+
+	switch mediaType {
+	case "text/event-stream",
+		"application/octet-stream",
+		"application/zip",
+		"application/gzip",
+		"application/x-gzip",
+		"application/x-bzip2",
+		"application/x-7z-compressed",
+		"application/x-rar-compressed",
+		"application/zstd":
+		return false
+	}
+
+	return true
+}
+
+// compressWriter buffers the start of the body until it knows whether the
+// response is worth compressing, then sends it encoded or as it is.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+
+		return
+	}
+
+	// Like net/http, ignore the status once the body was started.
+	if w.status != 0 {
+		return
+	}
+
+	w.status = status
+
+	// Bodyless responses need no waiting, and with nothing buffered
+	// deciding cannot fail.
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		_ = w.decide()
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(b) //nolint:wrapcheck
+		}
+
+		return w.ResponseWriter.Write(b) //nolint:wrapcheck
+	}
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.buf = append(w.buf, b...)
+
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush sends what is buffered so far, which for a body still under the
+// minimum size means sending it uncompressed.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide writes the header, compressing when the response allows, and the
+// buffered body.
+func (w *compressWriter) decide() error {
+	w.decided = true
+
+	h := w.Header()
+
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// Sniff before compressing, afterwards net/http would see the encoded bytes.
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if w.status == http.StatusNotModified {
+		// Keep the tag of the compressed response the client validates.
+		weakenETag(h)
+	}
+
+	if w.status == http.StatusOK && len(w.buf) >= w.minSize &&
+		h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		// The encoded bytes differ, so the strong tag of the original no longer applies.
+		weakenETag(h)
+
+		w.enc = encoderPools[w.encoding].Get().(encoder) //nolint:forcetypeassert
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	_, err := w.Write(buf)
+
+	return err
+}
+
+func (w *compressWriter) close() {
+	if !w.decided {
+		// The handler wrote nothing, net/http sends its default response.
+		if w.status == 0 {
+			return
+		}
+
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+
+	if w.enc == nil {
+		return
+	}
+
+	if err := w.enc.Close(); err == nil {
+		w.enc.Reset(nil)
+		encoderPools[w.encoding].Put(w.enc)
+	}
+
+	w.enc = nil
+}
+
+func weakenETag(h http.Header) {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+}
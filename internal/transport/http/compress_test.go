@@ -0,0 +1,256 @@
+package http_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	const minSize = 64
+
+	large := strings.Repeat(`{"text":"a reply"},`, 20)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		status         int
+		body           string
+		flush          bool
+		wantEncoding   string
+	}{
+		{
+			name:           "gzip",
+			acceptEncoding: "gzip",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "deflate",
+			acceptEncoding: "deflate",
+			body:           large,
+			wantEncoding:   "deflate",
+		},
+		{
+			name:           "brotli preferred",
+			acceptEncoding: "gzip, deflate, br",
+			body:           large,
+			wantEncoding:   "br",
+		},
+		{
+			name:           "quality",
+			acceptEncoding: "br;q=0.5, gzip;q=0.8",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "wildcard without brotli",
+			acceptEncoding: "br;q=0, *",
+			body:           large,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "identity only",
+			acceptEncoding: "identity",
+			body:           large,
+		},
+		{
+			name: "no accept-encoding",
+			body: large,
+		},
+		{
+			name:           "under the minimum size",
+			acceptEncoding: "gzip",
+			body:           `{"text":"short"}`,
+		},
+		{
+			name:           "compressed media",
+			acceptEncoding: "gzip",
+			contentType:    "image/png",
+			body:           large,
+		},
+		{
+			name:           "event stream",
+			acceptEncoding: "gzip",
+			contentType:    "text/event-stream",
+			body:           large,
+		},
+		{
+			name:           "flushed before the minimum size",
+			acceptEncoding: "gzip",
+			body:           large,
+			flush:          true,
+		},
+		{
+			name:           "error status",
+			acceptEncoding: "gzip",
+			status:         nethttp.StatusInternalServerError,
+			body:           large,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.Compress(minSize)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+
+				w.Header().Set("Content-Type", contentType)
+				w.Header().Set("ETag", `"1-abc"`)
+
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+
+				body := tt.body
+				if tt.flush {
+					io.WriteString(w, body[:10]) //nolint:errcheck
+					w.(nethttp.Flusher).Flush()
+					body = body[10:]
+				}
+
+				io.WriteString(w, body) //nolint:errcheck
+			}))
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			require.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+
+			if tt.status != 0 {
+				require.Equal(t, tt.status, w.Code)
+			}
+
+			var r io.Reader = w.Body
+
+			switch tt.wantEncoding {
+			case "":
+				require.Equal(t, `"1-abc"`, w.Header().Get("ETag"))
+			case "gzip":
+				zr, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+
+				r = zr
+			case "deflate":
+				zr, err := zlib.NewReader(w.Body)
+				require.NoError(t, err)
+
+				r = zr
+			case "br":
+				r = brotli.NewReader(w.Body)
+			}
+
+			if tt.wantEncoding != "" {
+				require.Equal(t, `W/"1-abc"`, w.Header().Get("ETag"))
+				require.Less(t, w.Body.Len(), len(tt.body))
+			}
+
+			body, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestCompress_NotModified(t *testing.T) {
+	t.Parallel()
+
+	h := http.Compress(0)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("ETag", `"1-abc"`)
+		w.WriteHeader(nethttp.StatusNotModified)
+	}))
+
+	req := httptest.NewRequest(nethttp.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	require.Equal(t, nethttp.StatusNotModified, w.Code)
+	require.Empty(t, w.Header().Get("Content-Encoding"))
+	require.Equal(t, `W/"1-abc"`, w.Header().Get("ETag"), "the tag matches the one of the compressed response")
+	require.Zero(t, w.Body.Len())
+}
+
+func TestCompress_GetThread(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	s := http.NewServer(http.Config{
+		Log: logger.TestLogger{},
+	}, usecase.NewUsecase(ds, usecase.Config{}))
+
+	req := postJSON("/board/1/thread", `{"title":"Title","text":"Text"}`, map[string]string{"board_id": "1"})
+	s.PostThread(httptest.NewRecorder(), req)
+
+	for i := 0; i < 20; i++ {
+		req = postJSON("/board/1/thread/1/comment", `{"text":"a reply long enough to compress"}`, map[string]string{"board_id": "1", "thread_id": "1"})
+		s.PostMessage(httptest.NewRecorder(), req)
+	}
+
+	h := http.Compress(1024)(nethttp.HandlerFunc(s.GetThread))
+
+	getThread := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodGet, "/board/1/thread/1", nil)
+		req = mux.SetURLVars(req, map[string]string{"board_id": "1", "thread_id": "1"})
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := getThread("")
+	require.Equal(t, nethttp.StatusOK, w.Code)
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	etag := w.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `W/"`))
+
+	zr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+
+	var thread models.MessageList
+
+	require.NoError(t, json.NewDecoder(zr).Decode(&thread))
+	require.Len(t, thread, 21)
+
+	// The weakened tag of the compressed response still validates.
+	w = getThread(etag)
+	require.Equal(t, nethttp.StatusNotModified, w.Code)
+	require.Equal(t, etag, w.Header().Get("ETag"))
+}
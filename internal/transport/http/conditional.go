@@ -0,0 +1,156 @@
+package http
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/pkg/data"
+)
+
+const (
+	// Boards are rarely added, so shared caches may serve the list for a while.
+	boardListCacheControl = "public, max-age=60"
+	// Boards and threads change on every post, caches revalidate them.
+	revalidateCacheControl = "public, no-cache"
+)
+
+// fingerprint hashes the fields of a response without encoding it, so an
+// unchanged response is recognized before any serialization.
+type fingerprint struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+func newFingerprint() *fingerprint {
+	return &fingerprint{h: fnv.New64a()}
+}
+
+func (f *fingerprint) uint(v uint64) {
+	binary.BigEndian.PutUint64(f.buf[:], v)
+	f.h.Write(f.buf[:]) //nolint:errcheck
+}
+
+func (f *fingerprint) string(s string) {
+	f.uint(uint64(len(s)))
+	f.h.Write([]byte(s)) //nolint:errcheck
+}
+
+func (f *fingerprint) message(m data.Message) {
+	f.uint(m.ID)
+	f.string(m.Title)
+	f.string(m.Text)
+	f.string(m.Content)
+	f.uint(uint64(m.Created.UnixNano()))
+	f.string(m.PosterID)
+
+	if m.Sage {
+		f.uint(1)
+	} else {
+		f.uint(0)
+	}
+}
+
+// etag is a strong entity tag made of the last message ID, so tags of a
+// growing thread are easy to tell apart, and the hash of everything shown.
+func (f *fingerprint) etag(lastID uint64) string {
+	return `"` + strconv.FormatUint(lastID, 10) + "-" + strconv.FormatUint(f.h.Sum64(), 16) + `"`
+}
+
+func boardsETag(boards data.GetBoardsResponse) string {
+	f := newFingerprint()
+
+	var lastID uint64
+
+	for _, b := range boards {
+		f.uint(b.ID)
+		f.string(b.Title)
+
+		if b.ID > lastID {
+			lastID = b.ID
+		}
+	}
+
+	return f.etag(lastID)
+}
+
+func messagesETag(messages data.GetThread) string {
+	f := newFingerprint()
+
+	var lastID uint64
+
+	for _, m := range messages {
+		f.message(m)
+
+		if m.ID > lastID {
+			lastID = m.ID
+		}
+	}
+
+	return f.etag(lastID)
+}
+
+// lastModified is the newest update time of the thread the messages are
+// from, which deletions and moderation move as well as posts.
+func lastModified(messages models.MessageList) time.Time {
+	var modified time.Time
+
+	for _, m := range messages {
+		if m.Updated.After(modified) {
+			modified = m.Updated
+		}
+	}
+
+	return modified
+}
+
+// notModified sets the validators of the response and, when the request
+// preconditions show the client already has it, answers 304 and returns true.
+// A zero modified time leaves Last-Modified out.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, modified time.Time) bool {
+	w.Header().Set("ETag", etag)
+
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if !fresh(r, etag, modified) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+// fresh evaluates If-None-Match and, only without it, If-Modified-Since,
+// as RFC 7232 orders them.
+func fresh(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if modified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified has a resolution of a second.
+	return !modified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// exposedHeaders are the response headers beyond the CORS-safelisted ones
+// that cross-origin clients get to read.
+const exposedHeaders = "ETag, Retry-After, " + RequestIDHeader
+
+type CORSConfig struct {
+	// Origins are the allowed origins, "*" for any. CORS is off when empty.
+	Origins []string
+	Methods []string
+	Headers []string
+	// Credentials lets requests carry cookies and the Authorization header.
+	// It is ignored for the "*" origin, which browsers do not allow with it.
+	Credentials bool
+	// MaxAge is how long browsers may cache a preflight response.
+	MaxAge time.Duration
+}
+
+// CORS allows browsers on the configured origins to call the API and
+// answers their preflight requests. It has to wrap the router, which does
+// not route OPTIONS requests.
+func CORS(cfg CORSConfig) mux.MiddlewareFunc {
+	anyOrigin := false
+	origins := make(map[string]bool, len(cfg.Origins))
+
+	for _, origin := range cfg.Origins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+
+		origins[strings.ToLower(origin)] = true
+	}
+
+	credentials := cfg.Credentials && !anyOrigin
+	methods := strings.Join(cfg.Methods, ", ")
+	headers := strings.Join(cfg.Headers, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge / time.Second))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+
+			// The allowed origin is echoed, so caches must keep one response per origin.
+			if !anyOrigin {
+				h.Add("Vary", "Origin")
+			}
+
+			origin := r.Header.Get("Origin")
+			if origin == "" || !anyOrigin && !origins[strings.ToLower(origin)] {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if anyOrigin {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if credentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				h.Set("Access-Control-Expose-Headers", exposedHeaders)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			h.Set("Access-Control-Allow-Methods", methods)
+
+			if headers != "" {
+				h.Set("Access-Control-Allow-Headers", headers)
+			}
+
+			if cfg.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	t.Parallel()
+
+	cfg := http.CORSConfig{
+		Origins:     []string{"https://frontend.example.com"},
+		Methods:     []string{"GET", "POST"},
+		Headers:     []string{"Content-Type"},
+		Credentials: true,
+		MaxAge:      10 * time.Minute,
+	}
+
+	tests := []struct {
+		name        string
+		cfg         http.CORSConfig
+		method      string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "allowed origin",
+			cfg:        cfg,
+			method:     nethttp.MethodGet,
+			origin:     "https://frontend.example.com",
+			wantStatus: nethttp.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://frontend.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "ETag, Retry-After, X-Request-ID",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:       "other origin",
+			cfg:        cfg,
+			method:     nethttp.MethodGet,
+			origin:     "https://evil.example.com",
+			wantStatus: nethttp.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			name:       "same origin",
+			cfg:        cfg,
+			method:     nethttp.MethodGet,
+			wantStatus: nethttp.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:       "preflight",
+			cfg:        cfg,
+			method:     nethttp.MethodOptions,
+			origin:     "https://frontend.example.com",
+			preflight:  true,
+			wantStatus: nethttp.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://frontend.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Content-Type",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:       "preflight from other origin",
+			cfg:        cfg,
+			method:     nethttp.MethodOptions,
+			origin:     "https://evil.example.com",
+			preflight:  true,
+			wantStatus: nethttp.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name:       "any origin",
+			cfg:        http.CORSConfig{Origins: []string{"*"}, Methods: []string{"GET"}, Credentials: true},
+			method:     nethttp.MethodGet,
+			origin:     "https://anyone.example.com",
+			wantStatus: nethttp.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+				"Vary":                             "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.CORS(tt.cfg)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {}))
+
+			req := httptest.NewRequest(tt.method, "/api/v1/board", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", nethttp.MethodPost)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			for name, want := range tt.wantHeaders {
+				require.Equal(t, want, w.Header().Get(name), name)
+			}
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
-	"io"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Batyachelly/goBoard/internal/database/models"
-	"github.com/Batyachelly/goBoard/internal/transport/http/data"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+	"github.com/Batyachelly/goBoard/pkg/data"
 
 	"github.com/gorilla/mux"
 )
@@ -17,14 +22,18 @@ import (
 // @Description  Get boards list
 // @Tags         main
 // @Produce      json
+// @Param        If-None-Match  header string  false  "ETag of the cached response"
 // @Success      200  {object}  data.GetBoardsResponse
+// @Success      304  "Not modified"
 // @Router       /board [get]
 func (s *Server) GetBoards(w http.ResponseWriter, r *http.Request) {
 	modelBoards, err := s.usecase.GetBoardList(r.Context())
 	if err != nil {
-		s.log.Error("GET boards: %w", err)
+		s.requestLog(r).Error("GET boards", "error", err)
 
 		s.responseJSON(w, http.StatusInternalServerError, nil)
+
+		return
 	}
 
 	dataBoards := make(data.GetBoardsResponse, 0, len(modelBoards))
@@ -36,6 +45,12 @@ func (s *Server) GetBoards(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	w.Header().Set("Cache-Control", boardListCacheControl)
+
+	if notModified(w, r, boardsETag(dataBoards), time.Time{}) {
+		return
+	}
+
 	s.responseJSON(w, http.StatusOK, dataBoards)
 }
 
@@ -45,7 +60,10 @@ func (s *Server) GetBoards(w http.ResponseWriter, r *http.Request) {
 // @Tags         main
 // @Produce      json
 // @Param        board_id   path int  true  "board ID"
+// @Param        If-None-Match      header string  false  "ETag of the cached response"
+// @Param        If-Modified-Since  header string  false  "Last-Modified of the cached response"
 // @Success      200  {object}  data.GetBoardResponse
+// @Success      304  "Not modified"
 // @Router       /board/{board_id} [get]
 func (s *Server) GetBoard(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -53,13 +71,17 @@ func (s *Server) GetBoard(w http.ResponseWriter, r *http.Request) {
 	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
 	}
 
 	modelBoard, err := s.usecase.GetBoard(r.Context(), boardID)
 	if err != nil {
-		s.log.Error("GET board: %w", err)
+		s.requestLog(r).Error("GET board", "error", err)
 
 		s.responseJSON(w, http.StatusInternalServerError, nil)
+
+		return
 	}
 
 	dataBoard := new(data.GetBoardResponse)
@@ -70,14 +92,22 @@ func (s *Server) GetBoard(w http.ResponseWriter, r *http.Request) {
 
 	for _, thread := range modelBoard.Threads {
 		dataBoard.Threads = append(dataBoard.Threads, data.Message{
-			ID:      thread.ID,
-			Title:   thread.Title,
-			Text:    thread.Text,
-			Content: thread.Content,
-			Created: thread.Created,
+			ID:       thread.ID,
+			Title:    thread.Title,
+			Text:     thread.Text,
+			Content:  thread.Content,
+			Created:  thread.Created,
+			PosterID: thread.PosterID,
+			Sage:     thread.Sage,
 		})
 	}
 
+	w.Header().Set("Cache-Control", revalidateCacheControl)
+
+	if notModified(w, r, messagesETag(dataBoard.Threads), modelBoard.Updated) {
+		return
+	}
+
 	s.responseJSON(w, http.StatusOK, dataBoard)
 }
 
@@ -88,7 +118,14 @@ func (s *Server) GetBoard(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        board_id   path int  true  "board ID"
 // @Param        thread_id  path int  true  "thread ID"
+// @Param        poster_id  query string  false  "only messages with this poster ID"
+// @Param        since      query int     false  "only messages after this message ID"
+// @Param        wait       query string  false  "wait this long for a new message, e.g. 30s"
+// @Param        If-None-Match      header string  false  "ETag of the cached response"
+// @Param        If-Modified-Since  header string  false  "Last-Modified of the cached response"
 // @Success      200  {object}  data.GetThread
+// @Success      304  "Not modified"
+// @Failure      404,503
 // @Router       /board/{board_id}/thread/{thread_id} [get]
 func (s *Server) GetThread(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -96,35 +133,144 @@ func (s *Server) GetThread(w http.ResponseWriter, r *http.Request) {
 	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
 	}
 
 	threadID, err := strconv.ParseUint(vars["thread_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	filter, err := s.threadFilter(r)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	ctx := r.Context()
+
+	if filter.Wait > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = s.untilShutdown(ctx)
+		defer cancel()
 	}
 
-	modelMessages, err := s.usecase.GetThread(r.Context(), boardID, threadID)
+	modelMessages, err := s.usecase.GetThread(ctx, boardID, threadID, filter)
 	if err != nil {
-		s.log.Error("GET thread: %w", err)
+		s.requestLog(r).Error("GET thread", "error", err)
 
-		s.responseJSON(w, http.StatusInternalServerError, nil)
+		code := errorStatus(err)
+		if code == http.StatusServiceUnavailable {
+			w.Header().Set("Retry-After", "1")
+		}
+
+		s.responseJSON(w, code, nil)
+
+		return
 	}
 
 	respMessages := make(data.GetThread, 0, len(modelMessages))
 
 	for _, modelMessage := range modelMessages {
 		respMessages = append(respMessages, data.Message{
-			ID:      modelMessage.ID,
-			Title:   modelMessage.Title,
-			Text:    modelMessage.Text,
-			Content: modelMessage.Content,
-			Created: modelMessage.Created,
+			ID:       modelMessage.ID,
+			Title:    modelMessage.Title,
+			Text:     modelMessage.Text,
+			Content:  modelMessage.Content,
+			Created:  modelMessage.Created,
+			PosterID: modelMessage.PosterID,
+			Sage:     modelMessage.Sage,
 		})
 	}
 
+	w.Header().Set("Cache-Control", revalidateCacheControl)
+
+	if notModified(w, r, messagesETag(respMessages), lastModified(modelMessages)) {
+		return
+	}
+
 	s.responseJSON(w, http.StatusOK, respMessages)
 }
 
+// untilShutdown derives a context cancelled when the server shuts down, for
+// the requests that would otherwise hold it up.
+func (s *Server) untilShutdown(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		select {
+		case <-s.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// errorStatus translates the usecase error kinds to HTTP statuses.
+func errorStatus(err error) int {
+	switch usecase.Classify(err) {
+	case usecase.KindInvalid:
+		return http.StatusBadRequest
+	case usecase.KindNotFound:
+		return http.StatusNotFound
+	case usecase.KindForbidden:
+		return http.StatusForbidden
+	case usecase.KindConflict:
+		return http.StatusConflict
+	case usecase.KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case usecase.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// threadFilter reads the GetThread query. The wait is cut short to leave
+// time for the response before the server write timeout.
+func (s *Server) threadFilter(r *http.Request) (models.ThreadFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.ThreadFilter{
+		PosterID: query.Get("poster_id"),
+	}
+
+	if since := query.Get("since"); since != "" {
+		id, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("parse since: %w", err)
+		}
+
+		filter.Since = id
+	}
+
+	if wait := query.Get("wait"); wait != "" {
+		d, err := time.ParseDuration(wait)
+		if err != nil {
+			return filter, fmt.Errorf("parse wait: %w", err)
+		}
+
+		if d < 0 {
+			return filter, errors.New("negative wait")
+		}
+
+		if d > s.maxWait {
+			d = s.maxWait
+		}
+
+		filter.Wait = d
+	}
+
+	return filter, nil
+}
+
 // Post thread
 // @Summary      Post thread
 // @Description  Create new thread by board ID
@@ -134,39 +280,50 @@ func (s *Server) GetThread(w http.ResponseWriter, r *http.Request) {
 // @Param        board_id   path int  true  "board ID"
 // @Param        thread body data.PostThreadRequest true "Thread create request"
 // @Success      200  {object}  data.PostThreadResponse
+// @Failure      403,404
+// @Failure      400,413,415  {object}  data.ErrorResponse
 // @Router       /board/{board_id}/thread [post]
 func (s *Server) PostThread(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		s.responseJSON(w, http.StatusBadRequest, nil)
-	}
-
 	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
 	}
 
 	thread := new(data.PostThreadRequest)
 
-	if err := json.Unmarshal(body, thread); err != nil {
-		s.responseJSON(w, http.StatusBadRequest, nil)
+	if !decodeJSON(w, r, thread) {
+		return
+	}
+
+	message := &models.Message{
+		BoardID:          boardID,
+		Title:            thread.Title,
+		Text:             thread.Text,
+		Content:          thread.Content,
+		IP:               ClientIP(r),
+		DeletionPassword: thread.Password,
 	}
 
-	threadID, err := s.usecase.PostThread(r.Context(), &models.Message{
-		BoardID: boardID,
-		Title:   thread.Title,
-		Text:    thread.Text,
-		Content: thread.Content,
-	})
+	threadID, err := s.usecase.PostThread(r.Context(), message)
 	if err != nil {
-		s.log.Error("POST thread: %w", err)
+		s.requestLog(r).Error("POST thread", "error", err)
 
-		s.responseJSON(w, http.StatusInternalServerError, nil)
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	resp := &data.PostThreadResponse{ThreadID: threadID}
+
+	if thread.Password == "" {
+		resp.DeletionToken = message.DeletionPassword
 	}
 
-	s.responseJSON(w, http.StatusOK, &data.PostThreadResponse{ThreadID: threadID})
+	s.responseJSON(w, http.StatusOK, resp)
 }
 
 // Post message
@@ -179,48 +336,108 @@ func (s *Server) PostThread(w http.ResponseWriter, r *http.Request) {
 // @Param        thread_id  path int  true  "thread ID"
 // @Param        thread body data.PostMessageRequest true "Message create request"
 // @Success      200  {object}  data.PostMessageResponse
-// @Router       /board/{board_id}/thread/{thread_id} [post]
+// @Failure      403,404
+// @Failure      400,413,415  {object}  data.ErrorResponse
+// @Router       /board/{board_id}/thread/{thread_id}/comment [post]
 func (s *Server) PostMessage(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
-	body, err := io.ReadAll(r.Body)
+	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	threadID, err := strconv.ParseUint(vars["thread_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
 	}
 
 	comment := new(data.PostMessageRequest)
 
-	if err := json.Unmarshal(body, comment); err != nil {
-		s.responseJSON(w, http.StatusBadRequest, nil)
+	if !decodeJSON(w, r, comment) {
+		return
+	}
+
+	message := &models.Message{
+		BoardID:          boardID,
+		ThreadID:         threadID,
+		Title:            comment.Title,
+		Text:             comment.Text,
+		Content:          comment.Content,
+		IP:               ClientIP(r),
+		DeletionPassword: comment.Password,
+		Sage:             comment.Sage || strings.EqualFold(strings.TrimSpace(comment.Email), "sage"),
 	}
 
+	messageID, err := s.usecase.PostMessage(r.Context(), message)
+	if err != nil {
+		s.requestLog(r).Error("POST message", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	resp := &data.PostMessageResponse{MessageID: messageID}
+
+	if comment.Password == "" {
+		resp.DeletionToken = message.DeletionPassword
+	}
+
+	s.responseJSON(w, http.StatusOK, resp)
+}
+
+// Delete message
+// @Summary      Delete own message
+// @Description  Delete a message, or only its content, with the password given or the token issued on posting
+// @Tags         main
+// @Accept       json
+// @Param        board_id    path int  true  "board ID"
+// @Param        message_id  path int  true  "message ID"
+// @Param        message body data.DeleteMessageRequest true "Message delete request"
+// @Success      200
+// @Failure      403,404,409
+// @Failure      400,413,415  {object}  data.ErrorResponse
+// @Router       /board/{board_id}/message/{message_id}/delete [post]
+func (s *Server) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
 	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
 	}
 
-	threadID, err := strconv.ParseUint(vars["thread_id"], 10, 64)
+	messageID, err := strconv.ParseUint(vars["message_id"], 10, 64)
 	if err != nil {
 		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
 	}
 
-	messageID, err := s.usecase.PostMessage(r.Context(), &models.Message{
-		BoardID:  boardID,
-		ThreadID: threadID,
-		Title:    comment.Title,
-		Text:     comment.Text,
-		Content:  comment.Content,
-	})
-	if err != nil {
-		s.log.Error("POST message: %w", err)
+	req := new(data.DeleteMessageRequest)
 
-		s.responseJSON(w, http.StatusInternalServerError, nil)
+	if !decodeJSON(w, r, req) {
+		return
+	}
+
+	if err := s.usecase.DeleteOwnMessage(r.Context(), boardID, messageID, req.Password, req.ContentOnly); err != nil {
+		s.requestLog(r).Error("POST delete message", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
 	}
 
-	s.responseJSON(w, http.StatusOK, &data.PostMessageResponse{MessageID: messageID})
+	s.responseJSON(w, http.StatusOK, nil)
 }
 
-func (s Server) responseJSON(w http.ResponseWriter, status int, data interface{}) {
+func (s *Server) responseJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 
 	if status != http.StatusOK {
@@ -228,6 +445,6 @@ func (s Server) responseJSON(w http.ResponseWriter, status int, data interface{}
 	}
 
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		s.log.Error("%w", err.Error)
+		s.log.Error("encode response", "error", err)
 	}
 }
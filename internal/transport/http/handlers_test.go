@@ -1,15 +1,19 @@
 package http_test
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	nethttp "net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Batyachelly/goBoard/generated/mocks"
 	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
 	"github.com/Batyachelly/goBoard/internal/database/models"
 	"github.com/Batyachelly/goBoard/internal/logger"
 	"github.com/Batyachelly/goBoard/internal/transport/http"
@@ -20,6 +24,13 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func postJSON(target, body string, vars map[string]string) *nethttp.Request {
+	req := httptest.NewRequest(nethttp.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	return mux.SetURLVars(req, vars)
+}
+
 func TestServer_GetBoards(t *testing.T) {
 	t.Parallel()
 
@@ -67,7 +78,7 @@ func TestServer_GetBoards(t *testing.T) {
 
 			s := http.NewServer(http.Config{
 				Log: logger.TestLogger{},
-			}, usecase.NewUsecase(tt.ds))
+			}, usecase.NewUsecase(tt.ds, usecase.Config{}))
 
 			s.GetBoards(w, req)
 
@@ -76,6 +87,8 @@ func TestServer_GetBoards(t *testing.T) {
 			wantJSON, _ := json.Marshal(tt.want)
 
 			require.JSONEq(t, string(wantJSON), string(body))
+			require.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+			require.NotEmpty(t, w.Header().Get("ETag"))
 		})
 	}
 }
@@ -159,7 +172,7 @@ func TestServer_GetBoard(t *testing.T) {
 
 			s := http.NewServer(http.Config{
 				Log: logger.TestLogger{},
-			}, usecase.NewUsecase(tt.ds))
+			}, usecase.NewUsecase(tt.ds, usecase.Config{}))
 
 			s.GetBoard(w, req)
 
@@ -171,3 +184,394 @@ func TestServer_GetBoard(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_PostThread(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	s := http.NewServer(http.Config{
+		Log: logger.TestLogger{},
+	}, usecase.NewUsecase(ds, usecase.Config{}))
+
+	req := postJSON("/board/1/thread", `{"title":"Title","text":"Text","password":"secret"}`, map[string]string{"board_id": "1"})
+	w := httptest.NewRecorder()
+
+	s.PostThread(w, req)
+
+	require.Equal(t, nethttp.StatusOK, w.Code)
+	require.JSONEq(t, `{"threadId":1}`, w.Body.String())
+
+	req = httptest.NewRequest("GET", "/board/1/thread/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"board_id": "1", "thread_id": "1"})
+	w = httptest.NewRecorder()
+
+	s.GetThread(w, req)
+
+	var thread models.MessageList
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &thread))
+	require.Len(t, thread, 1)
+	require.Equal(t, "Title", thread[0].Title)
+	require.Equal(t, "Text", thread[0].Text)
+}
+
+func TestServer_Post_Errors(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	s := http.NewServer(http.Config{
+		Log: logger.TestLogger{},
+	}, usecase.NewUsecase(ds, usecase.Config{DeletionWindow: time.Hour}))
+
+	req := postJSON("/board/1/thread", `{"title":"Title","text":"Text"}`, map[string]string{"board_id": "1"})
+	s.PostThread(httptest.NewRecorder(), req)
+
+	// bcrypt only takes passwords up to 72 bytes.
+	longPassword := strings.Repeat("p", 73)
+
+	tests := []struct {
+		name    string
+		handler func(w nethttp.ResponseWriter, r *nethttp.Request)
+		path    string
+		body    string
+		vars    map[string]string
+		want    int
+	}{
+		{
+			name:    "thread with a too long password",
+			handler: s.PostThread,
+			path:    "/board/1/thread",
+			body:    `{"title":"Title","text":"Text","password":"` + longPassword + `"}`,
+			vars:    map[string]string{"board_id": "1"},
+			want:    nethttp.StatusForbidden,
+		},
+		{
+			name:    "thread on a missing board",
+			handler: s.PostThread,
+			path:    "/board/2/thread",
+			body:    `{"title":"Title","text":"Text"}`,
+			vars:    map[string]string{"board_id": "2"},
+			want:    nethttp.StatusNotFound,
+		},
+		{
+			name:    "message with a too long password",
+			handler: s.PostMessage,
+			path:    "/board/1/thread/1/comment",
+			body:    `{"text":"Reply","password":"` + longPassword + `"}`,
+			vars:    map[string]string{"board_id": "1", "thread_id": "1"},
+			want:    nethttp.StatusForbidden,
+		},
+		{
+			name:    "message on a missing board",
+			handler: s.PostMessage,
+			path:    "/board/2/thread/1/comment",
+			body:    `{"text":"Reply"}`,
+			vars:    map[string]string{"board_id": "2", "thread_id": "1"},
+			want:    nethttp.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+
+			tt.handler(w, postJSON(tt.path, tt.body, tt.vars))
+
+			require.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestServer_GetThread_Conditional(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	s := http.NewServer(http.Config{
+		Log: logger.TestLogger{},
+	}, usecase.NewUsecase(ds, usecase.Config{}))
+
+	getThread := func(header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/board/1/thread/1", nil)
+		req = mux.SetURLVars(req, map[string]string{"board_id": "1", "thread_id": "1"})
+
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		w := httptest.NewRecorder()
+
+		s.GetThread(w, req)
+
+		return w
+	}
+
+	req := postJSON("/board/1/thread", `{"title":"Title","text":"Text"}`, map[string]string{"board_id": "1"})
+	s.PostThread(httptest.NewRecorder(), req)
+
+	w := getThread(nil)
+	require.Equal(t, nethttp.StatusOK, w.Code)
+	require.Equal(t, "public, no-cache", w.Header().Get("Cache-Control"))
+
+	etag := w.Header().Get("ETag")
+	require.Regexp(t, `^"1-[0-9a-f]+"$`, etag)
+
+	lastModified := w.Header().Get("Last-Modified")
+	modified, err := nethttp.ParseTime(lastModified)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), modified, time.Minute)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{
+			name:   "matching etag",
+			header: map[string]string{"If-None-Match": etag},
+			want:   nethttp.StatusNotModified,
+		},
+		{
+			name:   "one of the etags matches",
+			header: map[string]string{"If-None-Match": `"0-0", W/` + etag},
+			want:   nethttp.StatusNotModified,
+		},
+		{
+			name:   "any etag",
+			header: map[string]string{"If-None-Match": "*"},
+			want:   nethttp.StatusNotModified,
+		},
+		{
+			name:   "other etag",
+			header: map[string]string{"If-None-Match": `"0-0"`},
+			want:   nethttp.StatusOK,
+		},
+		{
+			name:   "not modified since",
+			header: map[string]string{"If-Modified-Since": lastModified},
+			want:   nethttp.StatusNotModified,
+		},
+		{
+			name:   "modified since",
+			header: map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(nethttp.TimeFormat)},
+			want:   nethttp.StatusOK,
+		},
+		{
+			name:   "invalid if-modified-since",
+			header: map[string]string{"If-Modified-Since": "yesterday"},
+			want:   nethttp.StatusOK,
+		},
+		{
+			name: "other etag wins over if-modified-since",
+			header: map[string]string{
+				"If-None-Match":     `"0-0"`,
+				"If-Modified-Since": lastModified,
+			},
+			want: nethttp.StatusOK,
+		},
+		{
+			name: "matching etag wins over if-modified-since",
+			header: map[string]string{
+				"If-None-Match":     etag,
+				"If-Modified-Since": modified.Add(-time.Second).Format(nethttp.TimeFormat),
+			},
+			want: nethttp.StatusNotModified,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := getThread(tt.header)
+
+			require.Equal(t, tt.want, w.Code)
+			require.Equal(t, etag, w.Header().Get("ETag"))
+			require.Equal(t, lastModified, w.Header().Get("Last-Modified"))
+
+			if tt.want == nethttp.StatusNotModified {
+				require.Empty(t, w.Body.String())
+			}
+		})
+	}
+
+	req = postJSON("/board/1/thread/1/comment", `{"text":"Reply"}`, map[string]string{"board_id": "1", "thread_id": "1"})
+	s.PostMessage(httptest.NewRecorder(), req)
+
+	w = getThread(map[string]string{"If-None-Match": etag})
+	require.Equal(t, nethttp.StatusOK, w.Code, "a reply changes the etag")
+
+	replyETag := w.Header().Get("ETag")
+	require.Regexp(t, `^"2-[0-9a-f]+"$`, replyETag)
+
+	replyModified := w.Header().Get("Last-Modified")
+
+	// Last-Modified has a resolution of a second.
+	waitNextSecond(t, replyModified)
+	require.NoError(t, ds.DeleteMessage(context.Background(), 1, 2))
+
+	w = getThread(map[string]string{"If-None-Match": replyETag})
+	require.Equal(t, nethttp.StatusOK, w.Code, "a deleted reply changes the etag")
+
+	w = getThread(map[string]string{"If-Modified-Since": replyModified})
+	require.Equal(t, nethttp.StatusOK, w.Code, "a deleted reply moves last-modified")
+	require.NotEqual(t, replyModified, w.Header().Get("Last-Modified"))
+}
+
+func TestServer_GetBoard_Conditional(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	s := http.NewServer(http.Config{
+		Log: logger.TestLogger{},
+	}, usecase.NewUsecase(ds, usecase.Config{}))
+
+	getBoard := func(header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/board/1", nil)
+		req = mux.SetURLVars(req, map[string]string{"board_id": "1"})
+
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+
+		w := httptest.NewRecorder()
+
+		s.GetBoard(w, req)
+
+		return w
+	}
+
+	for _, title := range []string{"First", "Second"} {
+		req := postJSON("/board/1/thread", `{"title":"`+title+`","text":"Text"}`, map[string]string{"board_id": "1"})
+		s.PostThread(httptest.NewRecorder(), req)
+	}
+
+	w := getBoard(nil)
+	require.Equal(t, nethttp.StatusOK, w.Code)
+
+	etag := w.Header().Get("ETag")
+	lastModified := w.Header().Get("Last-Modified")
+	require.NotEmpty(t, lastModified)
+
+	w = getBoard(map[string]string{"If-Modified-Since": lastModified})
+	require.Equal(t, nethttp.StatusNotModified, w.Code)
+	require.Empty(t, w.Body.String())
+
+	waitNextSecond(t, lastModified)
+	require.NoError(t, ds.DeleteMessage(context.Background(), 1, 1))
+
+	w = getBoard(map[string]string{"If-Modified-Since": lastModified})
+	require.Equal(t, nethttp.StatusOK, w.Code, "a deleted thread moves last-modified")
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+	require.NotEqual(t, lastModified, w.Header().Get("Last-Modified"))
+
+	w = getBoard(map[string]string{
+		"If-None-Match":     etag,
+		"If-Modified-Since": w.Header().Get("Last-Modified"),
+	})
+	require.Equal(t, nethttp.StatusOK, w.Code, "if-modified-since is ignored with if-none-match")
+}
+
+// waitNextSecond sleeps until a change is newer than the given
+// Last-Modified, which only has a resolution of a second.
+func waitNextSecond(t *testing.T, lastModified string) {
+	t.Helper()
+
+	modified, err := nethttp.ParseTime(lastModified)
+	require.NoError(t, err)
+
+	time.Sleep(time.Until(modified.Add(time.Second)))
+}
+
+func TestServer_GetThread_Since(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	s := http.NewServer(http.Config{
+		Log:          logger.TestLogger{},
+		WriteTimeout: 2 * time.Second,
+	}, usecase.NewUsecase(ds, usecase.Config{}))
+
+	req := postJSON("/board/1/thread", `{"title":"Title","text":"Text"}`, map[string]string{"board_id": "1"})
+	s.PostThread(httptest.NewRecorder(), req)
+
+	req = postJSON("/board/1/thread/1/comment", `{"text":"Reply"}`, map[string]string{"board_id": "1", "thread_id": "1"})
+	s.PostMessage(httptest.NewRecorder(), req)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantIDs  []uint64
+	}{
+		{
+			name:     "since the opening post",
+			query:    "since=1",
+			wantCode: nethttp.StatusOK,
+			wantIDs:  []uint64{2},
+		},
+		{
+			name:     "wait with nothing new",
+			query:    "since=2&wait=10ms",
+			wantCode: nethttp.StatusOK,
+			wantIDs:  []uint64{},
+		},
+		{
+			name:     "wait beyond the write timeout",
+			query:    "since=2&wait=1h",
+			wantCode: nethttp.StatusOK,
+			wantIDs:  []uint64{},
+		},
+		{
+			name:     "bad since",
+			query:    "since=first",
+			wantCode: nethttp.StatusBadRequest,
+		},
+		{
+			name:     "bad wait",
+			query:    "wait=forever",
+			wantCode: nethttp.StatusBadRequest,
+		},
+		{
+			name:     "negative wait",
+			query:    "wait=-1s",
+			wantCode: nethttp.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest("GET", "/board/1/thread/1?"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"board_id": "1", "thread_id": "1"})
+			w := httptest.NewRecorder()
+
+			start := time.Now()
+
+			s.GetThread(w, req)
+
+			require.Less(t, time.Since(start), 2*time.Second, "waits end before the write timeout")
+			require.Equal(t, tt.wantCode, w.Code)
+
+			if tt.wantCode != nethttp.StatusOK {
+				return
+			}
+
+			var thread models.MessageList
+
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &thread))
+
+			ids := []uint64{}
+			for _, m := range thread {
+				ids = append(ids, m.ID)
+			}
+
+			require.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
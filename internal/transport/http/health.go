@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Batyachelly/goBoard/internal/health"
+)
+
+// Liveness probe
+// @Summary      Liveness
+// @Description  Reports that the process is running
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Router       /healthz [get]
+func (s *Server) Healthz(w http.ResponseWriter, r *http.Request) {
+	s.responseJSON(w, http.StatusOK, health.Report{Status: health.StatusOK, Checks: []health.CheckResult{}})
+}
+
+// Readiness probe
+// @Summary      Readiness
+// @Description  Runs the dependency checks, failing while the server shuts down
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Failure      503  {object}  health.Report
+// @Router       /readyz [get]
+func (s *Server) Readyz(w http.ResponseWriter, r *http.Request) {
+	if s.health == nil {
+		s.Healthz(w, r)
+
+		return
+	}
+
+	report := s.health.Check(r.Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	s.responseJSON(w, status, report)
+}
@@ -1,26 +1,53 @@
 package http
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	_ "github.com/Batyachelly/goBoard/generated/swagger" // docs is generated by Swag CLI
+	"github.com/Batyachelly/goBoard/internal/health"
 	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/metrics"
+	"github.com/Batyachelly/goBoard/internal/tracing"
 	"github.com/Batyachelly/goBoard/internal/usecase"
 
 	"github.com/gorilla/mux"
 	swagger "github.com/swaggo/http-swagger"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 type Serve interface {
 	Serve() error
+	Shutdown(ctx context.Context) error
 }
 
 type Server struct {
 	server  *http.Server
 	usecase usecase.Usecaser
 	log     logger.Logger
+	health  *health.Registry
+	maxWait time.Duration
+
+	// shutdown is closed by Shutdown to end the long polls, which would
+	// otherwise keep the graceful shutdown waiting.
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+
+	listener   string
+	socket     string
+	socketMode os.FileMode
+
+	tlsCert   string
+	tlsKey    string
+	tlsReload time.Duration
 }
 
 type Config struct {
@@ -28,21 +55,93 @@ type Config struct {
 	WriteTimeout time.Duration
 	ReadTimeout  time.Duration
 	Log          logger.Logger
+	Health       *health.Registry
+	Metrics      *metrics.Metrics
+	MetricsPath  string
+	Tracer       *tracing.Tracer
+
+	Compress        bool
+	CompressMinSize int
+
+	// Listener is ListenerTCP, the default, ListenerUnix or ListenerSystemd.
+	Listener   string
+	Socket     string
+	SocketMode os.FileMode
+
+	// TLSCert and TLSKey turn on TLS, with HTTP/2. H2C serves HTTP/2
+	// without TLS instead.
+	TLSCert           string
+	TLSKey            string
+	TLSReloadInterval time.Duration
+	H2C               bool
+
+	// MaxBodySize caps request bodies, PostMaxBodySize those of new threads
+	// and replies. Zero leaves them unlimited.
+	MaxBodySize     int64
+	PostMaxBodySize int64
+
+	CORS    CORSConfig
+	CSP     string
+	DocsCSP string
+
+	// TrustedProxies are the addresses and networks of the proxies whose
+	// X-Forwarded-For and X-Real-IP headers give the client address.
+	TrustedProxies []string
+
+	ModeratorTokens []string
+	ReportLimit     int
+	ReportPeriod    time.Duration
 }
 
+const (
+	// writeMargin is left of the write timeout to send a long-polled response.
+	writeMargin = time.Second
+	// maxWait bounds long-polling when there is no write timeout.
+	maxWait = time.Minute
+)
+
 func NewServer(cfg Config, usecase usecase.Usecaser) *Server {
 	r := mux.NewRouter()
 
 	s := &Server{
 		usecase: usecase,
 		server: &http.Server{
-			Handler: r,
-
 			Addr:         cfg.Addr,
 			WriteTimeout: cfg.WriteTimeout,
 			ReadTimeout:  cfg.ReadTimeout,
 		},
-		log: cfg.Log,
+		log:     cfg.Log,
+		health:  cfg.Health,
+		maxWait: longPollLimit(cfg.WriteTimeout),
+
+		shutdown: make(chan struct{}),
+
+		listener:   cfg.Listener,
+		socket:     cfg.Socket,
+		socketMode: cfg.SocketMode,
+
+		tlsCert:   cfg.TLSCert,
+		tlsKey:    cfg.TLSKey,
+		tlsReload: cfg.TLSReloadInterval,
+	}
+
+	r.HandleFunc("/healthz", s.Healthz).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", s.Readyz).Methods(http.MethodGet)
+
+	if cfg.Metrics != nil {
+		if cfg.MetricsPath != "" {
+			r.Handle(cfg.MetricsPath, cfg.Metrics.Handler()).Methods(http.MethodGet)
+		}
+
+		r.Use(cfg.Metrics.Middleware)
+	}
+
+	if cfg.Tracer != nil {
+		r.Use(cfg.Tracer.Middleware, traceLog)
+	}
+
+	if cfg.Compress {
+		r.Use(Compress(cfg.CompressMinSize))
 	}
 
 	r.Use(CaptchaVerify)
@@ -53,16 +152,133 @@ func NewServer(cfg Config, usecase usecase.Usecaser) *Server {
 	sub.HandleFunc("/board/{board_id}", s.GetBoard).Methods(http.MethodGet)
 	sub.HandleFunc("/board/{board_id}/thread/{thread_id}", s.GetThread).Methods(http.MethodGet)
 
-	sub.HandleFunc("/board/{board_id}/thread", s.PostThread).Methods(http.MethodPost)
-	sub.HandleFunc("/board/{board_id}/thread/{thread_id}/comment", s.PostMessage).Methods(http.MethodPost)
+	limit := bodyLimit(cfg.MaxBodySize)
+	postLimit := bodyLimit(cfg.PostMaxBodySize)
 
-	r.PathPrefix("/swagger/").Handler(swagger.Handler(
+	sub.Handle("/board/{board_id}/thread", postLimit(s.BanCheck(http.HandlerFunc(s.PostThread)))).Methods(http.MethodPost)
+	sub.Handle("/board/{board_id}/thread/{thread_id}/comment", postLimit(s.BanCheck(http.HandlerFunc(s.PostMessage)))).Methods(http.MethodPost)
+
+	var postReport http.Handler = http.HandlerFunc(s.PostReport)
+	if cfg.ReportLimit > 0 && cfg.ReportPeriod > 0 {
+		postReport = RateLimit(cfg.ReportLimit, cfg.ReportPeriod)(postReport)
+	}
+
+	sub.Handle("/board/{board_id}/message/{message_id}/report", limit(postReport)).Methods(http.MethodPost)
+	sub.Handle("/board/{board_id}/message/{message_id}/delete", limit(http.HandlerFunc(s.DeleteMessage))).Methods(http.MethodPost)
+
+	mod := sub.PathPrefix("/mod").Subrouter()
+	mod.Use(ModeratorAuth(cfg.ModeratorTokens))
+
+	mod.HandleFunc("/report", s.GetReports).Methods(http.MethodGet)
+	mod.HandleFunc("/report/post", s.GetReportGroups).Methods(http.MethodGet)
+	mod.HandleFunc("/report/{report_id}/dismiss", s.DismissReport).Methods(http.MethodPost)
+	mod.HandleFunc("/report/{report_id}/action", s.ActOnReport).Methods(http.MethodPost)
+
+	mod.HandleFunc("/ban", s.GetBans).Methods(http.MethodGet)
+	mod.Handle("/ban", limit(http.HandlerFunc(s.PostBan))).Methods(http.MethodPost)
+	mod.HandleFunc("/ban/{ban_id}", s.DeleteBan).Methods(http.MethodDelete)
+	mod.Handle("/board/{board_id}/message/{message_id}/ban", limit(http.HandlerFunc(s.PostMessageBan))).Methods(http.MethodPost)
+	mod.HandleFunc("/board/{board_id}/message/{message_id}/author", s.GetMessageAuthor).Methods(http.MethodGet)
+
+	r.PathPrefix("/swagger/").Handler(ContentSecurityPolicy(cfg.DocsCSP)(swagger.Handler(
 		swagger.URL("doc.json"),
-	))
+	)))
+
+	// These wrap the router to cover the requests it does not route too:
+	// preflights, unknown paths and methods.
+	var handler http.Handler = r
+
+	if len(cfg.CORS.Origins) > 0 {
+		handler = CORS(cfg.CORS)(handler)
+	}
+
+	handler = SecurityHeaders(cfg.CSP)(handler)
+	// Inside RealIP, so the resolved client address is logged.
+	handler = AccessLog(cfg.Log)(handler)
+	handler = RealIP(cfg.TrustedProxies, cfg.Listener == ListenerUnix)(handler)
+
+	// With TLS, net/http negotiates HTTP/2 itself.
+	if cfg.H2C && cfg.TLSCert == "" {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
+
+	s.server.Handler = handler
 
 	return s
 }
 
+// Handler is the handler the server serves, for embedding the API in
+// another server or testing it.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+func bodyLimit(limit int64) mux.MiddlewareFunc {
+	if limit <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return MaxBodySize(limit)
+}
+
+// longPollLimit is the longest a request may wait for new messages and
+// still be answered within the write timeout.
+func longPollLimit(writeTimeout time.Duration) time.Duration {
+	if writeTimeout == 0 {
+		return maxWait
+	}
+
+	if writeTimeout <= writeMargin {
+		return 0
+	}
+
+	return writeTimeout - writeMargin
+}
+
+// Serve listens until the server fails or Shutdown is called, returning nil in the latter case.
 func (s *Server) Serve() error {
-	return fmt.Errorf("serve http: %w", s.server.ListenAndServe())
+	l, err := s.listen()
+	if err != nil {
+		return fmt.Errorf("serve http: %w", err)
+	}
+
+	if s.tlsCert == "" {
+		err = s.server.Serve(l)
+	} else {
+		err = s.serveTLS(l)
+	}
+
+	if !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve http: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) serveTLS(l net.Listener) error {
+	certs, err := newCertReloader(s.tlsCert, s.tlsKey, s.tlsReload, s.log)
+	if err != nil {
+		l.Close()
+
+		return err
+	}
+
+	s.server.TLSConfig = &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.GetCertificate,
+	}
+
+	return s.server.ServeTLS(l, "", "") //nolint:wrapcheck
+}
+
+// Shutdown ends the long polls, stops accepting connections and waits for
+// in-flight requests until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown http: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,213 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/health"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/metrics"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	s := http.NewServer(http.Config{
+		Addr: "127.0.0.1:0",
+		Log:  logger.TestLogger{},
+	}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, s.Shutdown(ctx))
+
+	select {
+	case err := <-serveErr:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Server.Serve() did not return after Shutdown")
+	}
+}
+
+// pollingUsecase reports the long polls reaching the usecase.
+type pollingUsecase struct {
+	usecase.Usecaser
+	polling chan struct{}
+}
+
+func (u pollingUsecase) GetThread(ctx context.Context, boardID, threadID uint64, filter models.ThreadFilter) (models.MessageList, error) {
+	if filter.Wait > 0 {
+		u.polling <- struct{}{}
+	}
+
+	return u.Usecaser.GetThread(ctx, boardID, threadID, filter) //nolint:wrapcheck
+}
+
+func TestServer_Shutdown_LongPoll(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+	uc := pollingUsecase{Usecaser: usecase.NewUsecase(ds, usecase.Config{}), polling: make(chan struct{}, 1)}
+
+	_, err := uc.PostThread(context.Background(), &models.Message{BoardID: 1, Title: "OP"})
+	require.NoError(t, err)
+
+	socket := filepath.Join(t.TempDir(), "goboard.sock")
+
+	s := http.NewServer(http.Config{
+		Log:      logger.TestLogger{},
+		Listener: http.ListenerUnix,
+		Socket:   socket,
+	}, uc)
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	client := unixClient(socket, nil)
+	client.Timeout = 5 * time.Second
+
+	resp := waitGet(t, client, "http://goboard/healthz")
+	resp.Body.Close()
+
+	polled := make(chan int, 1)
+
+	go func() {
+		resp, err := client.Get("http://goboard/api/v1/board/1/thread/1?since=1&wait=1m")
+		if err != nil {
+			polled <- 0
+
+			return
+		}
+
+		resp.Body.Close()
+		polled <- resp.StatusCode
+	}()
+
+	<-uc.polling
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, s.Shutdown(ctx), "the long poll does not hold up the shutdown")
+	require.Equal(t, nethttp.StatusOK, <-polled)
+	require.NoError(t, <-serveErr)
+}
+
+func TestServer_MetricsPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		metricsPath string
+		wantStatus  int
+	}{
+		{
+			name:        "served",
+			metricsPath: "/metrics",
+			wantStatus:  nethttp.StatusOK,
+		},
+		{
+			name:       "served elsewhere",
+			wantStatus: nethttp.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := http.NewServer(http.Config{
+				Log:         logger.TestLogger{},
+				Metrics:     metrics.New(),
+				MetricsPath: tt.metricsPath,
+			}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+			w := httptest.NewRecorder()
+			s.Handler().ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, "/metrics", nil))
+
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestServer_Readyz(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		check        health.Check
+		shuttingDown bool
+		wantStatus   int
+	}{
+		{
+			name:       "ready",
+			check:      func(ctx context.Context) error { return nil },
+			wantStatus: nethttp.StatusOK,
+		},
+		{
+			name:       "check failed",
+			check:      func(ctx context.Context) error { return errors.New("unreachable") },
+			wantStatus: nethttp.StatusServiceUnavailable,
+		},
+		{
+			name:         "shutting down",
+			check:        func(ctx context.Context) error { return nil },
+			shuttingDown: true,
+			wantStatus:   nethttp.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := health.NewRegistry(time.Second)
+			registry.Register("database", tt.check)
+
+			if tt.shuttingDown {
+				registry.SetShuttingDown()
+			}
+
+			s := http.NewServer(http.Config{
+				Log:    logger.TestLogger{},
+				Health: registry,
+			}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+			w := httptest.NewRecorder()
+			s.Readyz(w, httptest.NewRequest(nethttp.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			var report health.Report
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+			require.Equal(t, "database", report.Checks[0].Name)
+		})
+	}
+}
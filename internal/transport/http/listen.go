@@ -0,0 +1,105 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/activation"
+)
+
+const (
+	ListenerTCP     = "tcp"
+	ListenerUnix    = "unix"
+	ListenerSystemd = "systemd"
+)
+
+var (
+	ErrNoSystemdSocket = errors.New("no socket passed by systemd")
+	ErrUnknownListener = errors.New("unknown listener")
+	ErrSocketInUse     = errors.New("socket in use by another process")
+)
+
+// listen opens the configured listener, a TCP one when none is set.
+func (s *Server) listen() (net.Listener, error) {
+	switch s.listener {
+	case "", ListenerTCP:
+		addr := s.server.Addr
+		if addr == "" {
+			addr = ":http"
+		}
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("listen tcp: %w", err)
+		}
+
+		return l, nil
+	case ListenerUnix:
+		return listenUnix(s.socket, s.socketMode)
+	case ListenerSystemd:
+		listeners, err := activation.Listeners()
+		if err != nil {
+			return nil, fmt.Errorf("get systemd sockets: %w", err)
+		}
+
+		if len(listeners) == 0 || listeners[0] == nil {
+			return nil, ErrNoSystemdSocket
+		}
+
+		// Only the first socket is served, close the others.
+		for _, l := range listeners[1:] {
+			if l != nil {
+				l.Close()
+			}
+		}
+
+		return listeners[0], nil
+	default:
+		return nil, fmt.Errorf("%q: %w", s.listener, ErrUnknownListener)
+	}
+}
+
+// umaskMu serializes the umask changes of concurrent listenUnix calls,
+// as the umask is shared by the whole process.
+var umaskMu sync.Mutex
+
+// listenUnix listens on a Unix socket at path created with mode, replacing
+// the socket left behind by an earlier run. A socket another process still
+// accepts connections on is left alone.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		conn, err := net.DialTimeout("unix", path, time.Second)
+		if err == nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("listen unix %s: %w", path, ErrSocketInUse)
+		}
+
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("check stale socket: %w", err)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+
+	// The socket is created with the mode already, there is no window in
+	// which it is open to more users than configured.
+	umaskMu.Lock()
+	old := umask(int(0o777 &^ mode.Perm()))
+	l, err := net.Listen("unix", path)
+	umask(old)
+	umaskMu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("listen unix: %w", err)
+	}
+
+	return l, nil
+}
@@ -0,0 +1,291 @@
+package http_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/privacy"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Serve_Unix(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "goboard.sock")
+
+	// A socket left behind by an earlier run is replaced.
+	stale, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	s := http.NewServer(http.Config{
+		Log:        logger.TestLogger{},
+		Listener:   http.ListenerUnix,
+		Socket:     socket,
+		SocketMode: 0o600,
+	}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+	serve(t, s)
+
+	client := unixClient(socket, nil)
+
+	resp := waitGet(t, client, "http://goboard/healthz")
+	resp.Body.Close()
+	require.Equal(t, nethttp.StatusOK, resp.StatusCode)
+
+	fi, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestServer_Serve_UnixInUse(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "goboard.sock")
+
+	// Another process still serving on the socket keeps it.
+	running, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	defer running.Close()
+
+	s := http.NewServer(http.Config{
+		Log:        logger.TestLogger{},
+		Listener:   http.ListenerUnix,
+		Socket:     socket,
+		SocketMode: 0o660,
+	}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+	require.ErrorIs(t, s.Serve(), http.ErrSocketInUse)
+
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestServer_Serve_UnixClientIP(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "goboard.sock")
+
+	ips, err := privacy.NewIPProtector(privacy.Config{
+		HashKey:       "secret",
+		EncryptionKey: strings.Repeat("ab", 32),
+	})
+	require.NoError(t, err)
+
+	uc := usecase.NewUsecase(memory.NewDatabaseService(memory.Config{Boards: []string{"General"}}), usecase.Config{
+		IPProtector: ips,
+		IPRetention: time.Hour,
+	})
+
+	s := http.NewServer(http.Config{
+		Log:          logger.TestLogger{},
+		Listener:     http.ListenerUnix,
+		Socket:       socket,
+		SocketMode:   0o600,
+		ReportLimit:  1,
+		ReportPeriod: time.Hour,
+	}, uc)
+
+	serve(t, s)
+
+	client := unixClient(socket, nil)
+
+	resp := waitGet(t, client, "http://goboard/healthz")
+	resp.Body.Close()
+
+	post := func(path, body, forwardedFor string) int {
+		req, err := nethttp.NewRequest(nethttp.MethodPost, "http://goboard/api/v1"+path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", "application/json")
+
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	thread := `{"title":"Title","text":"Text"}`
+
+	// The proxy on the socket passes the client address on.
+	require.Equal(t, nethttp.StatusOK, post("/board/1/thread", thread, "198.51.100.7"))
+
+	author, err := uc.GetMessageAuthor(context.Background(), 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, "198.51.100.7", author.IP)
+
+	// Without it the address is unknown, which is not an error.
+	require.Equal(t, nethttp.StatusOK, post("/board/1/thread", thread, ""))
+
+	_, err = uc.GetMessageAuthor(context.Background(), 1, 2)
+	require.ErrorIs(t, err, usecase.ErrUnknownAuthor)
+
+	_, err = uc.PostBan(context.Background(), &models.Ban{Network: "198.51.100.7", Reason: "spam"})
+	require.NoError(t, err)
+
+	require.Equal(t, nethttp.StatusForbidden, post("/board/1/thread", thread, "198.51.100.7"))
+	require.Equal(t, nethttp.StatusOK, post("/board/1/thread", thread, "198.51.100.8"))
+
+	// Clients behind the proxy are rate limited one by one.
+	report := `{"reason":"spam"}`
+
+	require.Equal(t, nethttp.StatusOK, post("/board/1/message/1/report", report, "198.51.100.8"))
+	require.Equal(t, nethttp.StatusTooManyRequests, post("/board/1/message/1/report", report, "198.51.100.8"))
+	require.Equal(t, nethttp.StatusOK, post("/board/1/message/1/report", report, "198.51.100.9"))
+}
+
+func TestServer_Serve_TLSReload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "goboard.sock")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	writeCert(t, certFile, keyFile, 1, time.Now().Add(-time.Hour))
+
+	s := http.NewServer(http.Config{
+		Log:               logger.TestLogger{},
+		Listener:          http.ListenerUnix,
+		Socket:            socket,
+		SocketMode:        0o600,
+		TLSCert:           certFile,
+		TLSKey:            keyFile,
+		TLSReloadInterval: time.Nanosecond,
+	}, usecase.NewUsecase(&mocks.Databaser{}, usecase.Config{}))
+
+	serve(t, s)
+
+	client := unixClient(socket, &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec
+	})
+
+	resp := waitGet(t, client, "https://localhost/healthz")
+	resp.Body.Close()
+	require.Equal(t, nethttp.StatusOK, resp.StatusCode)
+	require.Equal(t, "HTTP/2.0", resp.Proto)
+	require.Equal(t, int64(1), resp.TLS.PeerCertificates[0].SerialNumber.Int64())
+
+	// The renewed certificate is served to new connections without a restart.
+	writeCert(t, certFile, keyFile, 2, time.Now())
+
+	resp, err := client.Get("https://localhost/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, int64(2), resp.TLS.PeerCertificates[0].SerialNumber.Int64())
+}
+
+func serve(t *testing.T, s *http.Server) {
+	t.Helper()
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.Serve()
+	}()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, s.Shutdown(ctx))
+		require.NoError(t, <-serveErr)
+	})
+}
+
+// unixClient dials socket for every request, on a new connection each time.
+func unixClient(socket string, tlsConfig *tls.Config) *nethttp.Client {
+	return &nethttp.Client{
+		Timeout: time.Second,
+		Transport: &nethttp.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+
+				return d.DialContext(ctx, "unix", socket)
+			},
+			TLSClientConfig:   tlsConfig,
+			ForceAttemptHTTP2: true,
+			DisableKeepAlives: true,
+		},
+	}
+}
+
+// waitGet retries until the server started in the background accepts the request.
+func waitGet(t *testing.T, client *nethttp.Client, url string) *nethttp.Response {
+	t.Helper()
+
+	var (
+		resp *nethttp.Response
+		err  error
+	)
+
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Get(url); err == nil {
+			return resp
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.NoError(t, err)
+
+	return resp
+}
+
+func writeCert(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	// Set the times explicitly, a rewrite within the file system time
+	// resolution would otherwise look unchanged.
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
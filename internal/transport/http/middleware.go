@@ -1,9 +1,67 @@
 package http
 
-import "net/http"
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type contextKey int
+
+const (
+	moderatorKey contextKey = iota
+	clientIPKey
+)
 
 func CaptchaVerify(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// ModeratorAuth accepts requests carrying one of the configured moderator
+// tokens as a bearer token and stores the moderator name in the request context.
+// Tokens are given as "name:token" pairs.
+func ModeratorAuth(tokens []string) mux.MiddlewareFunc {
+	type moderator struct {
+		name  string
+		token []byte
+	}
+
+	moderators := make([]moderator, 0, len(tokens))
+
+	for _, pair := range tokens {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+
+		moderators = append(moderators, moderator{name: parts[0], token: []byte(parts[1])})
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := []byte(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+
+			for _, m := range moderators {
+				if subtle.ConstantTimeCompare(token, m.token) == 1 {
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), moderatorKey, m.name)))
+
+					return
+				}
+			}
+
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+}
+
+// Moderator returns the name of the moderator authenticated by ModeratorAuth.
+func Moderator(ctx context.Context) string {
+	name, _ := ctx.Value(moderatorKey).(string)
+
+	return name
+}
@@ -0,0 +1,80 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeratorAuth(t *testing.T) {
+	t.Parallel()
+
+	tokens := []string{"alice:secret", "bob:hunter2", "broken", ":nameless", "empty:"}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      int
+		wantModerator string
+	}{
+		{
+			name:          "1",
+			authorization: "Bearer secret",
+			wantCode:      nethttp.StatusOK,
+			wantModerator: "alice",
+		},
+		{
+			name:          "2",
+			authorization: "Bearer hunter2",
+			wantCode:      nethttp.StatusOK,
+			wantModerator: "bob",
+		},
+		{
+			name:          "3 error, wrong token",
+			authorization: "Bearer secret2",
+			wantCode:      nethttp.StatusUnauthorized,
+		},
+		{
+			name:     "4 error, no token",
+			wantCode: nethttp.StatusUnauthorized,
+		},
+		{
+			name:          "5 error, empty token",
+			authorization: "Bearer ",
+			wantCode:      nethttp.StatusUnauthorized,
+		},
+		{
+			name:          "6 error, name as token",
+			authorization: "Bearer nameless",
+			wantCode:      nethttp.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var moderator string
+
+			h := http.ModeratorAuth(tokens)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				moderator = http.Moderator(r.Context())
+			}))
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/mod/report", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			require.Equal(t, tt.wantModerator, moderator)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	period    time.Duration
+	lastSweep time.Time
+	limiters  map[string]*clientLimiter
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimit allows each client IP at most limit requests per period.
+// Clients with an unknown address are not limited, rather than sharing
+// one allowance.
+func RateLimit(limit int, period time.Duration) func(http.Handler) http.Handler {
+	rl := &rateLimiter{
+		limit:    rate.Every(period / time.Duration(limit)),
+		burst:    limit,
+		period:   period,
+		limiters: make(map[string]*clientLimiter),
+	}
+
+	retryAfter := strconv.Itoa(int((period/time.Duration(limit) + time.Second - 1) / time.Second))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := ClientIP(r); ip != "" && !rl.allow(ip, time.Now()) {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (rl *rateLimiter) allow(key string, now time.Time) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) > rl.period {
+		for k, cl := range rl.limiters {
+			if now.Sub(cl.lastSeen) > rl.period {
+				delete(rl.limiters, k)
+			}
+		}
+
+		rl.lastSweep = now
+	}
+
+	cl, ok := rl.limiters[key]
+	if !ok {
+		cl = &clientLimiter{limiter: rate.NewLimiter(rl.limit, rl.burst)}
+		rl.limiters[key] = cl
+	}
+
+	cl.lastSeen = now
+
+	return cl.limiter.AllowN(now, 1)
+}
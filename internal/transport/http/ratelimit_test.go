@@ -0,0 +1,41 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	h := http.RateLimit(2, time.Hour)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {}))
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, "/board/1/message/1/report", nil)
+		req.RemoteAddr = remoteAddr
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		return w
+	}
+
+	require.Equal(t, nethttp.StatusOK, do("198.51.100.7:1000").Code)
+	require.Equal(t, nethttp.StatusOK, do("198.51.100.7:1001").Code, "the limit is per address, not per connection")
+
+	w := do("198.51.100.7:1002")
+	require.Equal(t, nethttp.StatusTooManyRequests, w.Code)
+	require.Equal(t, "1800", w.Header().Get("Retry-After"))
+
+	require.Equal(t, nethttp.StatusOK, do("198.51.100.8:1000").Code, "other clients have their own allowance")
+
+	for i := 0; i < 3; i++ {
+		require.Equal(t, nethttp.StatusOK, do("@").Code, "clients with an unknown address are not limited")
+	}
+}
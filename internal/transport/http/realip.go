@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP resolves the client address once per request for ClientIP. Behind
+// a proxy, one on a Unix socket or with an address in trusted, which holds
+// addresses and CIDR networks, it is read from X-Forwarded-For or X-Real-IP.
+// A peer that is not an IP address, as on Unix sockets, is always a proxy:
+// the client address is then unknown unless the proxy passes it on.
+func RealIP(trusted []string, unixSocket bool) func(http.Handler) http.Handler {
+	networks := make([]*net.IPNet, 0, len(trusted))
+
+	for _, entry := range trusted {
+		if network := parseNetwork(entry); network != nil {
+			networks = append(networks, network)
+		}
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer := remoteIP(r.RemoteAddr)
+
+			ip := ""
+			if peer != nil {
+				ip = peer.String()
+			}
+
+			if unixSocket || peer == nil || isTrusted(peer) {
+				if forwarded := forwardedIP(r.Header, isTrusted); forwarded != "" {
+					ip = forwarded
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey, ip)))
+		})
+	}
+}
+
+// forwardedIP is the client address passed on by the proxies, the last
+// untrusted one of X-Forwarded-For since earlier ones can be forged by the
+// client.
+func forwardedIP(h http.Header, isTrusted func(net.IP) bool) string {
+	if values := h.Values("X-Forwarded-For"); len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				return ""
+			}
+
+			if i == 0 || !isTrusted(ip) {
+				return ip.String()
+			}
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(h.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+// ClientIP is the client address, empty when it is unknown.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey).(string); ok {
+		return ip
+	}
+
+	if ip := remoteIP(r.RemoteAddr); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+func remoteIP(addr string) net.IP {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	return net.ParseIP(host)
+}
+
+func parseNetwork(entry string) *net.IPNet {
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		return network
+	}
+
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil
+	}
+
+	bits := 8 * net.IPv4len
+	if ip.To4() == nil {
+		bits = 8 * net.IPv6len
+	} else {
+		ip = ip.To4()
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
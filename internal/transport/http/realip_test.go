@@ -0,0 +1,109 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealIP(t *testing.T) {
+	t.Parallel()
+
+	trusted := []string{"10.0.0.0/8", "192.0.2.1"}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		unixSocket bool
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "198.51.100.7:4321",
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "forged header from untrusted peer",
+			remoteAddr: "198.51.100.7:4321",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.9"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "192.0.2.1:4321",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "trusted proxy chain",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.9, 10.0.0.3"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "all hops trusted",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.3"},
+			want:       "10.0.0.4",
+		},
+		{
+			name:       "real ip header",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "invalid forwarded address",
+			remoteAddr: "10.0.0.2:4321",
+			headers:    map[string]string{"X-Forwarded-For": "unknown"},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "unix socket peer",
+			remoteAddr: "@",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "unix socket peer without header",
+			remoteAddr: "@",
+			want:       "",
+		},
+		{
+			name:       "unix socket listener",
+			remoteAddr: "198.51.100.7:4321",
+			unixSocket: true,
+			headers:    map[string]string{"X-Real-IP": "203.0.113.9"},
+			want:       "203.0.113.9",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+
+			h := http.RealIP(trusted, tt.unixSocket)(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+				got = http.ClientIP(r)
+			}))
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
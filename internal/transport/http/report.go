@@ -0,0 +1,236 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/pkg/data"
+
+	"github.com/gorilla/mux"
+)
+
+var reportStatuses = map[string]int{
+	"open":      models.ReportOpen,
+	"dismissed": models.ReportDismissed,
+	"actioned":  models.ReportActioned,
+}
+
+// Post report
+// @Summary      Report message
+// @Description  Report a message to moderators
+// @Tags         report
+// @Accept       json
+// @Produce      json
+// @Param        board_id    path int  true  "board ID"
+// @Param        message_id  path int  true  "message ID"
+// @Param        report body data.PostReportRequest true "Report request"
+// @Success      200  {object}  data.PostReportResponse
+// @Failure      404,429
+// @Failure      400,413,415  {object}  data.ErrorResponse
+// @Router       /board/{board_id}/message/{message_id}/report [post]
+func (s *Server) PostReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	boardID, err := strconv.ParseUint(vars["board_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	messageID, err := strconv.ParseUint(vars["message_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	req := new(data.PostReportRequest)
+
+	if !decodeJSON(w, r, req) {
+		return
+	}
+
+	reportID, err := s.usecase.PostReport(r.Context(), &models.Report{
+		BoardID:   boardID,
+		MessageID: messageID,
+		Reason:    req.Reason,
+		Comment:   req.Comment,
+	})
+	if err != nil {
+		s.requestLog(r).Error("POST report", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.responseJSON(w, http.StatusOK, &data.PostReportResponse{ReportID: reportID})
+}
+
+// Get reports
+// @Summary      Get reports
+// @Description  Get reports queue filtered by status
+// @Tags         moderation
+// @Produce      json
+// @Param        status  query string  false  "open, dismissed or actioned"  default(open)
+// @Success      200  {object}  data.GetReportsResponse
+// @Security     ModeratorToken
+// @Router       /mod/report [get]
+func (s *Server) GetReports(w http.ResponseWriter, r *http.Request) {
+	status, ok := parseReportStatus(r)
+	if !ok {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	modelReports, err := s.usecase.GetReportList(r.Context(), status)
+	if err != nil {
+		s.requestLog(r).Error("GET reports", "error", err)
+
+		s.responseJSON(w, http.StatusInternalServerError, nil)
+
+		return
+	}
+
+	s.responseJSON(w, http.StatusOK, reportsToData(modelReports))
+}
+
+// Get reports grouped by post
+// @Summary      Get reports grouped by post
+// @Description  Get reports queue filtered by status and grouped by reported message
+// @Tags         moderation
+// @Produce      json
+// @Param        status  query string  false  "open, dismissed or actioned"  default(open)
+// @Success      200  {object}  data.GetReportGroupsResponse
+// @Security     ModeratorToken
+// @Router       /mod/report/post [get]
+func (s *Server) GetReportGroups(w http.ResponseWriter, r *http.Request) {
+	status, ok := parseReportStatus(r)
+	if !ok {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	modelGroups, err := s.usecase.GetReportGroupList(r.Context(), status)
+	if err != nil {
+		s.requestLog(r).Error("GET report groups", "error", err)
+
+		s.responseJSON(w, http.StatusInternalServerError, nil)
+
+		return
+	}
+
+	respGroups := make(data.GetReportGroupsResponse, 0, len(modelGroups))
+
+	for _, group := range modelGroups {
+		respGroups = append(respGroups, data.ReportGroup{
+			BoardID:   group.BoardID,
+			MessageID: group.MessageID,
+			Reports:   reportsToData(group.Reports),
+		})
+	}
+
+	s.responseJSON(w, http.StatusOK, respGroups)
+}
+
+// Dismiss report
+// @Summary      Dismiss report
+// @Description  Close report without acting on the reported message
+// @Tags         moderation
+// @Param        report_id  path int  true  "report ID"
+// @Success      200
+// @Failure      404,409
+// @Security     ModeratorToken
+// @Router       /mod/report/{report_id}/dismiss [post]
+func (s *Server) DismissReport(w http.ResponseWriter, r *http.Request) {
+	reportID, err := strconv.ParseUint(mux.Vars(r)["report_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	if err := s.usecase.DismissReport(r.Context(), reportID); err != nil {
+		s.requestLog(r).Error("POST dismiss report", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.responseJSON(w, http.StatusOK, nil)
+}
+
+// Act on report
+// @Summary      Act on report
+// @Description  Delete the reported message and resolve all reports filed against it
+// @Tags         moderation
+// @Param        report_id  path int  true  "report ID"
+// @Success      200
+// @Failure      404,409
+// @Security     ModeratorToken
+// @Router       /mod/report/{report_id}/action [post]
+func (s *Server) ActOnReport(w http.ResponseWriter, r *http.Request) {
+	reportID, err := strconv.ParseUint(mux.Vars(r)["report_id"], 10, 64)
+	if err != nil {
+		s.responseJSON(w, http.StatusBadRequest, nil)
+
+		return
+	}
+
+	if err := s.usecase.ActOnReport(r.Context(), reportID); err != nil {
+		s.requestLog(r).Error("POST act on report", "error", err)
+
+		s.responseJSON(w, errorStatus(err), nil)
+
+		return
+	}
+
+	s.requestLog(r).Info("moderator acted on report", "moderator", Moderator(r.Context()), "report_id", reportID)
+
+	s.responseJSON(w, http.StatusOK, nil)
+}
+
+func parseReportStatus(r *http.Request) (int, bool) {
+	name := r.URL.Query().Get("status")
+	if name == "" {
+		return models.ReportOpen, true
+	}
+
+	status, ok := reportStatuses[name]
+
+	return status, ok
+}
+
+func reportStatusName(status int) string {
+	for name, s := range reportStatuses {
+		if s == status {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func reportsToData(modelReports models.ReportList) []data.Report {
+	respReports := make([]data.Report, 0, len(modelReports))
+
+	for _, report := range modelReports {
+		respReports = append(respReports, data.Report{
+			ID:        report.ID,
+			BoardID:   report.BoardID,
+			MessageID: report.MessageID,
+			Reason:    report.Reason,
+			Comment:   report.Comment,
+			Status:    reportStatusName(report.Status),
+			Created:   report.Created,
+			Resolved:  report.Resolved,
+		})
+	}
+
+	return respReports
+}
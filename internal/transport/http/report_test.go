@@ -0,0 +1,161 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+	"github.com/Batyachelly/goBoard/pkg/data"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func newReportServer(t *testing.T) (*http.Server, *memory.DatabaseService) {
+	t.Helper()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+
+	for _, title := range []string{"first", "second"} {
+		_, _, err := ds.PostThread(context.Background(), &models.Message{BoardID: 1, Title: title}, nil)
+		require.NoError(t, err)
+	}
+
+	return http.NewServer(http.Config{Log: logger.TestLogger{}}, usecase.NewUsecase(ds, usecase.Config{})), ds
+}
+
+func TestServer_PostReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		messageID string
+		body      string
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			name:      "1",
+			messageID: "1",
+			body:      `{"reason":"spam","comment":"ads"}`,
+			wantCode:  nethttp.StatusOK,
+			wantBody:  `{"reportId":1}`,
+		},
+		{
+			name:      "2 error, unknown reason",
+			messageID: "1",
+			body:      `{"reason":"boring"}`,
+			wantCode:  nethttp.StatusBadRequest,
+		},
+		{
+			name:      "3 error, unknown message",
+			messageID: "99",
+			body:      `{"reason":"spam"}`,
+			wantCode:  nethttp.StatusNotFound,
+		},
+		{
+			name:      "4 error, invalid message id",
+			messageID: "first",
+			body:      `{"reason":"spam"}`,
+			wantCode:  nethttp.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _ := newReportServer(t)
+
+			req := postJSON("/board/1/message/"+tt.messageID+"/report", tt.body,
+				map[string]string{"board_id": "1", "message_id": tt.messageID})
+			w := httptest.NewRecorder()
+
+			s.PostReport(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+
+			if tt.wantBody != "" {
+				require.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_Reports(t *testing.T) {
+	t.Parallel()
+
+	s, ds := newReportServer(t)
+
+	for _, messageID := range []uint64{1, 2, 1} {
+		_, err := ds.PostReport(context.Background(), &models.Report{BoardID: 1, MessageID: messageID, Reason: models.ReportReasonSpam})
+		require.NoError(t, err)
+	}
+
+	getReports := func(target string) (int, data.GetReportsResponse) {
+		w := httptest.NewRecorder()
+		s.GetReports(w, httptest.NewRequest(nethttp.MethodGet, target, nil))
+
+		var reports data.GetReportsResponse
+		if w.Code == nethttp.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reports))
+		}
+
+		return w.Code, reports
+	}
+
+	resolve := func(handler nethttp.HandlerFunc, reportID string) int {
+		req := httptest.NewRequest(nethttp.MethodPost, "/mod/report/"+reportID, nil)
+		w := httptest.NewRecorder()
+		handler(w, mux.SetURLVars(req, map[string]string{"report_id": reportID}))
+
+		return w.Code
+	}
+
+	code, reports := getReports("/mod/report")
+	require.Equal(t, nethttp.StatusOK, code)
+	require.Len(t, reports, 3)
+	require.Equal(t, "open", reports[0].Status)
+
+	code, _ = getReports("/mod/report?status=closed")
+	require.Equal(t, nethttp.StatusBadRequest, code)
+
+	w := httptest.NewRecorder()
+	s.GetReportGroups(w, httptest.NewRequest(nethttp.MethodGet, "/mod/report/post", nil))
+	require.Equal(t, nethttp.StatusOK, w.Code)
+
+	var groups data.GetReportGroupsResponse
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+	require.Len(t, groups, 2)
+	require.Equal(t, uint64(1), groups[0].MessageID)
+	require.Len(t, groups[0].Reports, 2)
+
+	require.Equal(t, nethttp.StatusOK, resolve(s.DismissReport, "2"))
+	require.Equal(t, nethttp.StatusConflict, resolve(s.DismissReport, "2"))
+	require.Equal(t, nethttp.StatusConflict, resolve(s.ActOnReport, "2"))
+	require.Equal(t, nethttp.StatusNotFound, resolve(s.DismissReport, "99"))
+	require.Equal(t, nethttp.StatusNotFound, resolve(s.ActOnReport, "99"))
+	require.Equal(t, nethttp.StatusBadRequest, resolve(s.ActOnReport, "first"))
+
+	require.Equal(t, nethttp.StatusOK, resolve(s.ActOnReport, "1"))
+	require.Equal(t, nethttp.StatusConflict, resolve(s.ActOnReport, "3"), "reports on the message are resolved with it")
+
+	code, reports = getReports("/mod/report?status=actioned")
+	require.Equal(t, nethttp.StatusOK, code)
+	require.Len(t, reports, 2)
+
+	code, reports = getReports("/mod/report?status=dismissed")
+	require.Equal(t, nethttp.StatusOK, code)
+	require.Len(t, reports, 1)
+
+	_, err := ds.GetMessage(context.Background(), 1, 1)
+	require.Error(t, err, "the reported message is deleted")
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// SecurityHeaders sets the headers keeping browsers from sniffing content
+// types, framing the responses and leaking the referrer, and csp as the
+// Content-Security-Policy unless a route sets its own.
+func SecurityHeaders(csp string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("Referrer-Policy", "no-referrer")
+
+			if csp != "" {
+				h.Set("Content-Security-Policy", csp)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ContentSecurityPolicy replaces the policy set by SecurityHeaders for the
+// routes it wraps, an empty csp removing it.
+func ContentSecurityPolicy(csp string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if csp == "" {
+				w.Header().Del("Content-Security-Policy")
+			} else {
+				w.Header().Set("Content-Security-Policy", csp)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package http_test
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Parallel()
+
+	api := nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {})
+
+	tests := []struct {
+		name    string
+		handler nethttp.Handler
+		wantCSP string
+	}{
+		{
+			name:    "default policy",
+			handler: http.SecurityHeaders("default-src 'none'")(api),
+			wantCSP: "default-src 'none'",
+		},
+		{
+			name:    "route policy",
+			handler: http.SecurityHeaders("default-src 'none'")(http.ContentSecurityPolicy("default-src 'self'")(api)),
+			wantCSP: "default-src 'self'",
+		},
+		{
+			name:    "route without policy",
+			handler: http.SecurityHeaders("default-src 'none'")(http.ContentSecurityPolicy("")(api)),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, "/", nil))
+
+			require.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			require.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+			require.Equal(t, "no-referrer", w.Header().Get("Referrer-Policy"))
+			require.Equal(t, tt.wantCSP, w.Header().Get("Content-Security-Policy"))
+		})
+	}
+}
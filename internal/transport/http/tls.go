@@ -0,0 +1,103 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/logger"
+)
+
+// certReloader serves a certificate loaded from files and loads it again
+// once the files change, checking at most every interval. A renewed
+// certificate is picked up without a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	log      logger.Logger
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTimes [2]time.Time
+	checked  time.Time
+}
+
+func newCertReloader(certFile, keyFile string, interval time.Duration, log logger.Logger) (*certReloader, error) {
+	c := &certReloader{certFile: certFile, keyFile: keyFile, interval: interval, log: log}
+
+	modTimes, err := c.stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.load(modTimes); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.checked) < c.interval {
+		return c.cert, nil
+	}
+
+	c.checked = time.Now()
+
+	modTimes, err := c.stat()
+	if err != nil {
+		c.log.Error("check tls certificate", "error", err)
+
+		return c.cert, nil
+	}
+
+	if modTimes[0].Equal(c.modTimes[0]) && modTimes[1].Equal(c.modTimes[1]) {
+		return c.cert, nil
+	}
+
+	// A half-written pair fails to load, the old certificate stays in use
+	// and the next check tries again.
+	if err := c.load(modTimes); err != nil {
+		c.log.Error("reload tls certificate", "error", err)
+
+		return c.cert, nil
+	}
+
+	c.log.Info("tls certificate reloaded", "cert", c.certFile)
+
+	return c.cert, nil
+}
+
+func (c *certReloader) load(modTimes [2]time.Time) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("load tls certificate: %w", err)
+	}
+
+	c.cert = &cert
+	c.modTimes = modTimes
+	c.checked = time.Now()
+
+	return nil
+}
+
+func (c *certReloader) stat() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+
+	for i, name := range []string{c.certFile, c.keyFile} {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return modTimes, fmt.Errorf("stat tls file: %w", err)
+		}
+
+		modTimes[i] = fi.ModTime()
+	}
+
+	return modTimes, nil
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+// +build !windows
+
+package http
+
+import "syscall"
+
+// umask sets the file mode creation mask and returns the previous one.
+func umask(mask int) int {
+	return syscall.Umask(mask)
+}
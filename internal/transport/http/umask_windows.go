@@ -0,0 +1,6 @@
+package http
+
+// umask does nothing, Windows has no file mode creation mask.
+func umask(int) int {
+	return 0
+}
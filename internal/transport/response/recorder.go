@@ -0,0 +1,52 @@
+// Package response holds the response writer wrappers shared by the HTTP
+// middlewares.
+package response
+
+import "net/http"
+
+// Recorder is a ResponseWriter remembering the status and the size of the
+// response it writes. It flushes through to the wrapped writer, and Unwrap
+// gives http.ResponseController access to it.
+type Recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// NewRecorder wraps w. The status is http.StatusOK until set.
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+// Status is the status the response was sent with.
+func (r *Recorder) Status() int {
+	return r.status
+}
+
+// Bytes is the count of body bytes written.
+func (r *Recorder) Bytes() int {
+	return r.bytes
+}
+
+func (r *Recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *Recorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+
+	return n, err //nolint:wrapcheck
+}
+
+// Flush sends the buffered response when the wrapped writer supports it.
+func (r *Recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,71 @@
+package response_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/transport/response"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		handler    func(w http.ResponseWriter)
+		wantStatus int
+		wantBytes  int
+	}{
+		{
+			name:       "1 implicit ok",
+			handler:    func(w http.ResponseWriter) { _, _ = w.Write([]byte("hello")) },
+			wantStatus: http.StatusOK,
+			wantBytes:  5,
+		},
+		{
+			name:       "2 explicit status",
+			handler:    func(w http.ResponseWriter) { w.WriteHeader(http.StatusTeapot) },
+			wantStatus: http.StatusTeapot,
+		},
+		{
+			name: "3 flushed",
+			handler: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte("hi"))
+				w.(http.Flusher).Flush()
+			},
+			wantStatus: http.StatusOK,
+			wantBytes:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			rec := response.NewRecorder(w)
+
+			tt.handler(rec)
+
+			require.Equal(t, tt.wantStatus, rec.Status())
+			require.Equal(t, tt.wantBytes, rec.Bytes())
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Same(t, w, rec.Unwrap())
+		})
+	}
+
+	t.Run("4 flush reaches the wrapped writer", func(t *testing.T) {
+		t.Parallel()
+
+		w := httptest.NewRecorder()
+
+		response.NewRecorder(w).Flush()
+
+		require.True(t, w.Flushed)
+	})
+}
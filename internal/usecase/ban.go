@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+)
+
+// GetActiveBans returns the unexpired bans matching ip on the given board,
+// an empty list meaning the address is allowed to post. No ban matches an
+// unknown address.
+func (s *Usecase) GetActiveBans(ctx context.Context, ip string, boardID uint64) (models.BanList, error) {
+	if net.ParseIP(ip) == nil {
+		return models.BanList{}, nil
+	}
+
+	var ipHash string
+
+	if s.ips != nil {
+		hash, err := s.ips.Hash(ip)
+		if err != nil {
+			return nil, fmt.Errorf("usecase hash ip for bans: %w", err)
+		}
+
+		ipHash = hash
+	}
+
+	bans, err := s.ds.GetActiveBans(ctx, ip, ipHash, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("usecase get active bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+// PostBan bans a network, or an address hash when no network is given.
+func (s *Usecase) PostBan(ctx context.Context, ban *models.Ban) (uint64, error) {
+	if ban.Network != "" || ban.IPHash == "" {
+		network, err := normalizeNetwork(ban.Network)
+		if err != nil {
+			return 0, err
+		}
+
+		ban.Network = network
+	}
+
+	banID, err := s.ds.PostBan(ctx, ban)
+	if err != nil {
+		return 0, fmt.Errorf("usecase post ban: %w", err)
+	}
+
+	return banID, nil
+}
+
+// BanMessageAuthor bans the address the message was posted from, falling back
+// to its hash when the address itself has already been purged.
+func (s *Usecase) BanMessageAuthor(ctx context.Context, boardID, messageID uint64, ban *models.Ban) (uint64, error) {
+	author, err := s.GetMessageAuthor(ctx, boardID, messageID)
+	if err != nil {
+		return 0, err
+	}
+
+	ban.Network = author.IP
+	ban.IPHash = author.IPHash
+
+	return s.PostBan(ctx, ban)
+}
+
+func (s *Usecase) GetBanList(ctx context.Context) (models.BanList, error) {
+	bans, err := s.ds.GetBanList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("usecase get bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+func (s *Usecase) DeleteBan(ctx context.Context, banID uint64) error {
+	if err := s.ds.DeleteBan(ctx, banID); err != nil {
+		return fmt.Errorf("usecase delete ban: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeNetwork turns an IP address or CIDR range into canonical CIDR
+// notation, single addresses becoming /32 or /128 networks.
+func normalizeNetwork(network string) (string, error) {
+	if strings.Contains(network, "/") {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return "", fmt.Errorf("usecase parse ban network %q: %w", network, ErrInvalidBanNetwork)
+		}
+
+		return ipNet.String(), nil
+	}
+
+	ip := net.ParseIP(network)
+	if ip == nil {
+		return "", fmt.Errorf("usecase parse ban address %q: %w", network, ErrInvalidBanNetwork)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}).String(), nil
+	}
+
+	return (&net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}).String(), nil
+}
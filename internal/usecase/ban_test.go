@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/privacy"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUsecase_PostBan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		network     string
+		wantNetwork string
+		wantErr     error
+	}{
+		{
+			name:        "1 ipv4 address",
+			network:     "192.0.2.10",
+			wantNetwork: "192.0.2.10/32",
+		},
+		{
+			name:        "2 ipv4 range with host bits",
+			network:     "192.0.2.10/24",
+			wantNetwork: "192.0.2.0/24",
+		},
+		{
+			name:        "3 ipv6 address",
+			network:     "2001:db8::1",
+			wantNetwork: "2001:db8::1/128",
+		},
+		{
+			name:        "4 ipv6 range",
+			network:     "2001:db8:abcd::/48",
+			wantNetwork: "2001:db8:abcd::/48",
+		},
+		{
+			name:    "5 error, garbage",
+			network: "localhost",
+			wantErr: usecase.ErrInvalidBanNetwork,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds := &mocks.Databaser{}
+			ds.On("PostBan", mock.Anything, &models.Ban{Network: tt.wantNetwork}).Maybe().Return(uint64(1), nil)
+
+			_, err := usecase.NewUsecase(ds, usecase.Config{}).PostBan(context.Background(), &models.Ban{Network: tt.network})
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.PostBan() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			ds.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_GetActiveBans(t *testing.T) {
+	t.Parallel()
+
+	ips, err := privacy.NewIPProtector(privacy.Config{
+		HashKey:       "secret",
+		EncryptionKey: strings.Repeat("ab", 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ipHash, _ := ips.Hash("198.51.100.7")
+
+	tests := []struct {
+		name       string
+		ip         string
+		ips        *privacy.IPProtector
+		wantIP     string
+		wantIPHash string
+		wantQuery  bool
+	}{
+		{
+			name:       "1",
+			ip:         "198.51.100.7",
+			ips:        ips,
+			wantIP:     "198.51.100.7",
+			wantIPHash: ipHash,
+			wantQuery:  true,
+		},
+		{
+			name:      "2 without hash key",
+			ip:        "198.51.100.7",
+			wantIP:    "198.51.100.7",
+			wantQuery: true,
+		},
+		{
+			name: "3 unknown address",
+			ip:   "",
+			ips:  ips,
+		},
+		{
+			name: "4 unix socket peer",
+			ip:   "@",
+			ips:  ips,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds := &mocks.Databaser{}
+			if tt.wantQuery {
+				ds.On("GetActiveBans", mock.Anything, tt.wantIP, tt.wantIPHash, uint64(101)).Once().Return(models.BanList{{ID: 1}}, nil)
+			}
+
+			bans, err := usecase.NewUsecase(ds, usecase.Config{IPProtector: tt.ips}).GetActiveBans(context.Background(), tt.ip, 101)
+			if err != nil {
+				t.Fatalf("Usecase.GetActiveBans() error = %v", err)
+			}
+
+			if tt.wantQuery != (len(bans) == 1) {
+				t.Errorf("Usecase.GetActiveBans() = %v", bans)
+			}
+
+			ds.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_BanMessageAuthor(t *testing.T) {
+	t.Parallel()
+
+	ips, err := privacy.NewIPProtector(privacy.Config{
+		HashKey:       "secret",
+		EncryptionKey: strings.Repeat("ab", 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ipHash, _ := ips.Hash("198.51.100.7")
+	ipEncrypted, _ := ips.Encrypt("198.51.100.7")
+
+	tests := []struct {
+		name    string
+		message *models.Message
+		wantBan *models.Ban
+		wantErr error
+	}{
+		{
+			name:    "1",
+			message: &models.Message{ID: 202, BoardID: 101, IPHash: ipHash, IPEncrypted: ipEncrypted},
+			wantBan: &models.Ban{BoardID: 101, Network: "198.51.100.7/32", IPHash: ipHash, Reason: "spam"},
+		},
+		{
+			name:    "2 address purged",
+			message: &models.Message{ID: 202, BoardID: 101, IPHash: ipHash},
+			wantBan: &models.Ban{BoardID: 101, IPHash: ipHash, Reason: "spam"},
+		},
+		{
+			name:    "3 error, author unknown",
+			message: &models.Message{ID: 202, BoardID: 101},
+			wantErr: usecase.ErrUnknownAuthor,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds := &mocks.Databaser{}
+			ds.On("GetMessage", mock.Anything, uint64(101), uint64(202)).Once().Return(tt.message, nil)
+
+			if tt.wantBan != nil {
+				ds.On("PostBan", mock.Anything, tt.wantBan).Once().Return(uint64(303), nil)
+			}
+
+			_, err := usecase.NewUsecase(ds, usecase.Config{IPProtector: ips}).BanMessageAuthor(context.Background(), 101, 202, &models.Ban{
+				BoardID: 101,
+				Reason:  "spam",
+			})
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.BanMessageAuthor() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			ds.AssertExpectations(t)
+		})
+	}
+}
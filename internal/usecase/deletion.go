@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	deletionTokenLen       = 16
+	maxDeletionPasswordLen = 72
+)
+
+// protectDeletionPassword hashes the author deletion password of a message
+// about to be stored. When the author gave no password a random token is
+// issued and left in DeletionPassword for the caller to return.
+func (s *Usecase) protectDeletionPassword(message *models.Message) error {
+	if s.deletionWindow <= 0 {
+		return nil
+	}
+
+	if len(message.DeletionPassword) > maxDeletionPasswordLen {
+		return fmt.Errorf("usecase check deletion password length: %w", ErrInvalidPassword)
+	}
+
+	if message.DeletionPassword == "" {
+		token := make([]byte, deletionTokenLen)
+
+		if _, err := rand.Read(token); err != nil {
+			return fmt.Errorf("usecase make deletion token: %w", err)
+		}
+
+		message.DeletionPassword = hex.EncodeToString(token)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(message.DeletionPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("usecase hash deletion password: %w", err)
+	}
+
+	message.DeletionHash = string(hash)
+
+	return nil
+}
+
+// DeleteOwnMessage lets the author delete a message, or only its content,
+// with the deletion password within the configured window.
+func (s *Usecase) DeleteOwnMessage(ctx context.Context, boardID, messageID uint64, password string, contentOnly bool) error {
+	message, err := s.ds.GetMessage(ctx, boardID, messageID)
+	if err != nil {
+		return fmt.Errorf("usecase get own message: %w", err)
+	}
+
+	if message.DeletionHash == "" || s.deletionWindow <= 0 {
+		return fmt.Errorf("usecase message %d is not deletable: %w", messageID, ErrInvalidPassword)
+	}
+
+	if time.Since(message.Created) > s.deletionWindow {
+		return fmt.Errorf("usecase message %d posted %s: %w", messageID, message.Created, ErrDeletionWindowPassed)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(message.DeletionHash), []byte(password)); err != nil {
+		return fmt.Errorf("usecase compare deletion password: %w", ErrInvalidPassword)
+	}
+
+	if contentOnly {
+		if err := s.ds.DeleteMessageContent(ctx, boardID, messageID); err != nil {
+			return fmt.Errorf("usecase delete own message content: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := s.ds.DeleteMessage(ctx, boardID, messageID); err != nil {
+		return fmt.Errorf("usecase delete own message: %w", err)
+	}
+
+	return nil
+}
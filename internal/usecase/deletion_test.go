@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUsecase_DeleteOwnMessage(t *testing.T) {
+	t.Parallel()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		password    string
+		contentOnly bool
+		created     time.Time
+		wantCall    string
+		wantErr     error
+	}{
+		{
+			name:     "1",
+			password: "hunter2",
+			created:  time.Now(),
+			wantCall: "DeleteMessage",
+		},
+		{
+			name:        "2 content only",
+			password:    "hunter2",
+			contentOnly: true,
+			created:     time.Now(),
+			wantCall:    "DeleteMessageContent",
+		},
+		{
+			name:     "3 error, wrong password",
+			password: "hunter3",
+			created:  time.Now(),
+			wantErr:  usecase.ErrInvalidPassword,
+		},
+		{
+			name:     "4 error, window passed",
+			password: "hunter2",
+			created:  time.Now().Add(-2 * time.Hour),
+			wantErr:  usecase.ErrDeletionWindowPassed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ds := &mocks.Databaser{}
+			ds.On("GetMessage", mock.Anything, uint64(101), uint64(202)).Once().Return(&models.Message{
+				ID:           202,
+				BoardID:      101,
+				Created:      tt.created,
+				DeletionHash: string(hash),
+			}, nil)
+
+			if tt.wantCall != "" {
+				ds.On(tt.wantCall, mock.Anything, uint64(101), uint64(202)).Once().Return(nil)
+			}
+
+			s := usecase.NewUsecase(ds, usecase.Config{DeletionWindow: time.Hour})
+
+			err := s.DeleteOwnMessage(context.Background(), 101, 202, tt.password, tt.contentOnly)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.DeleteOwnMessage() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			ds.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+)
+
+var (
+	ErrInvalidReportReason  = errors.New("invalid report reason")
+	ErrInvalidReportComment = errors.New("invalid report comment")
+	ErrReportResolved       = errors.New("report already resolved")
+	ErrInvalidBanNetwork    = errors.New("invalid ban network")
+	ErrUnknownAuthor        = errors.New("message author address is unknown")
+	ErrInvalidPassword      = errors.New("invalid deletion password")
+	ErrDeletionWindowPassed = errors.New("deletion window has passed")
+	ErrTooManyWaiters       = errors.New("too many waiting requests")
+)
+
+// ErrorKind is the class of a usecase error the transports answer with
+// their own status codes.
+type ErrorKind int
+
+const (
+	// KindInternal is an unexpected error not shown to the client.
+	KindInternal ErrorKind = iota
+	KindInvalid
+	KindNotFound
+	KindForbidden
+	// KindConflict is a request the current state of the resource rejects.
+	KindConflict
+	// KindUnprocessable is a valid request the usecase can't act on.
+	KindUnprocessable
+	KindUnavailable
+	KindCanceled
+)
+
+// String is the snake case name of the kind, as used in metric labels.
+func (k ErrorKind) String() string {
+	switch k {
+	case KindInvalid:
+		return "invalid"
+	case KindNotFound:
+		return "not_found"
+	case KindForbidden:
+		return "forbidden"
+	case KindConflict:
+		return "conflict"
+	case KindUnprocessable:
+		return "unprocessable"
+	case KindUnavailable:
+		return "unavailable"
+	case KindCanceled:
+		return "canceled"
+	default:
+		return "internal"
+	}
+}
+
+// Classify returns the kind of an error returned by the usecase.
+func Classify(err error) ErrorKind {
+	switch {
+	case errors.Is(err, ErrInvalidReportReason),
+		errors.Is(err, ErrInvalidReportComment),
+		errors.Is(err, ErrInvalidBanNetwork):
+		return KindInvalid
+	case errors.Is(err, database.ErrNotFound):
+		return KindNotFound
+	case errors.Is(err, ErrInvalidPassword):
+		return KindForbidden
+	case errors.Is(err, ErrReportResolved),
+		errors.Is(err, ErrDeletionWindowPassed),
+		errors.Is(err, database.ErrConflict):
+		return KindConflict
+	case errors.Is(err, ErrUnknownAuthor):
+		return KindUnprocessable
+	case errors.Is(err, ErrTooManyWaiters):
+		return KindUnavailable
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return KindCanceled
+	default:
+		return KindInternal
+	}
+}
@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		err   error
+		want  usecase.ErrorKind
+		label string
+	}{
+		{
+			name:  "1 invalid report reason",
+			err:   usecase.ErrInvalidReportReason,
+			want:  usecase.KindInvalid,
+			label: "invalid",
+		},
+		{
+			name:  "2 wrapped not found",
+			err:   fmt.Errorf("get thread: %w", database.ErrNotFound),
+			want:  usecase.KindNotFound,
+			label: "not_found",
+		},
+		{
+			name:  "3 invalid password",
+			err:   usecase.ErrInvalidPassword,
+			want:  usecase.KindForbidden,
+			label: "forbidden",
+		},
+		{
+			name:  "4 resolved report",
+			err:   usecase.ErrReportResolved,
+			want:  usecase.KindConflict,
+			label: "conflict",
+		},
+		{
+			name:  "5 database conflict",
+			err:   fmt.Errorf("resolve report: %w", database.ErrConflict),
+			want:  usecase.KindConflict,
+			label: "conflict",
+		},
+		{
+			name:  "6 unknown author",
+			err:   usecase.ErrUnknownAuthor,
+			want:  usecase.KindUnprocessable,
+			label: "unprocessable",
+		},
+		{
+			name:  "7 too many waiters",
+			err:   usecase.ErrTooManyWaiters,
+			want:  usecase.KindUnavailable,
+			label: "unavailable",
+		},
+		{
+			name:  "8 deadline",
+			err:   fmt.Errorf("query: %w", context.DeadlineExceeded),
+			want:  usecase.KindCanceled,
+			label: "canceled",
+		},
+		{
+			name:  "9 unexpected",
+			err:   errors.New("connection refused"),
+			want:  usecase.KindInternal,
+			label: "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, usecase.Classify(tt.err))
+			require.Equal(t, tt.label, usecase.Classify(tt.err).String())
+		})
+	}
+}
@@ -9,7 +9,23 @@ import (
 type Usecaser interface {
 	GetBoardList(ctx context.Context) (models.BoardList, error)
 	GetBoard(ctx context.Context, boardID uint64) (*models.Board, error)
-	GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error)
+	GetThread(ctx context.Context, boardID, threadID uint64, filter models.ThreadFilter) (models.MessageList, error)
 	PostThread(ctx context.Context, thread *models.Message) (uint64, error)
 	PostMessage(ctx context.Context, message *models.Message) (uint64, error)
+	DeleteOwnMessage(ctx context.Context, boardID, messageID uint64, password string, contentOnly bool) error
+
+	PostReport(ctx context.Context, report *models.Report) (uint64, error)
+	GetReportList(ctx context.Context, status int) (models.ReportList, error)
+	GetReportGroupList(ctx context.Context, status int) (models.ReportGroupList, error)
+	DismissReport(ctx context.Context, reportID uint64) error
+	ActOnReport(ctx context.Context, reportID uint64) error
+
+	GetActiveBans(ctx context.Context, ip string, boardID uint64) (models.BanList, error)
+	PostBan(ctx context.Context, ban *models.Ban) (uint64, error)
+	BanMessageAuthor(ctx context.Context, boardID, messageID uint64, ban *models.Ban) (uint64, error)
+	GetBanList(ctx context.Context) (models.BanList, error)
+	DeleteBan(ctx context.Context, banID uint64) error
+
+	GetMessageAuthor(ctx context.Context, boardID, messageID uint64) (*models.MessageAuthor, error)
+	PurgeMessageIPs(ctx context.Context) (int64, error)
 }
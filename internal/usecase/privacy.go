@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/models"
+)
+
+// protectIP fills the address hash and encrypted address of a message
+// about to be stored.
+func (s *Usecase) protectIP(message *models.Message) error {
+	if s.ips == nil || message.IP == "" {
+		return nil
+	}
+
+	hash, err := s.ips.Hash(message.IP)
+	if err != nil {
+		return fmt.Errorf("usecase hash poster ip: %w", err)
+	}
+
+	encrypted, err := s.ips.Encrypt(message.IP)
+	if err != nil {
+		return fmt.Errorf("usecase encrypt poster ip: %w", err)
+	}
+
+	message.IPHash = hash
+	message.IPEncrypted = encrypted
+
+	return nil
+}
+
+// posterIDsEnabled reports whether the message should get a poster ID,
+// which needs both the board setting and a configured hash key.
+func (s *Usecase) posterIDsEnabled(ctx context.Context, message *models.Message) (bool, error) {
+	if s.ips == nil || message.IP == "" {
+		return false, nil
+	}
+
+	settings, err := s.ds.GetBoardSettings(ctx, message.BoardID)
+	if err != nil {
+		return false, fmt.Errorf("usecase get board settings: %w", err)
+	}
+
+	return settings.PosterIDs, nil
+}
+
+func (s *Usecase) assignPosterID(message *models.Message) error {
+	posterID, err := s.ips.PosterID(message.IP, message.ThreadID, time.Now())
+	if err != nil {
+		return fmt.Errorf("usecase make poster id: %w", err)
+	}
+
+	message.PosterID = posterID
+
+	return nil
+}
+
+// GetMessageAuthor reveals the stored address of a message author to moderators.
+// IP is empty once the retention period has passed.
+func (s *Usecase) GetMessageAuthor(ctx context.Context, boardID, messageID uint64) (*models.MessageAuthor, error) {
+	message, err := s.ds.GetMessage(ctx, boardID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("usecase get message for author: %w", err)
+	}
+
+	if message.IPHash == "" {
+		return nil, fmt.Errorf("usecase get message %d author: %w", messageID, ErrUnknownAuthor)
+	}
+
+	author := &models.MessageAuthor{IPHash: message.IPHash}
+
+	if s.ips != nil && message.IPEncrypted != nil {
+		ip, err := s.ips.Decrypt(message.IPEncrypted)
+		if err != nil {
+			return nil, fmt.Errorf("usecase decrypt message author: %w", err)
+		}
+
+		author.IP = ip
+	}
+
+	return author, nil
+}
+
+// PurgeMessageIPs wipes encrypted addresses older than the retention period,
+// address hashes are kept for ban matching.
+func (s *Usecase) PurgeMessageIPs(ctx context.Context) (int64, error) {
+	purged, err := s.ds.PurgeMessageIPs(ctx, time.Now().Add(-s.ipRetention))
+	if err != nil {
+		return 0, fmt.Errorf("usecase purge message ips: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+)
+
+const maxReportCommentLen = 1024
+
+func isReportReason(reason string) bool {
+	switch reason {
+	case models.ReportReasonIllegal,
+		models.ReportReasonSpam,
+		models.ReportReasonRules,
+		models.ReportReasonOffTopic,
+		models.ReportReasonOther:
+		return true
+	}
+
+	return false
+}
+
+func (s *Usecase) PostReport(ctx context.Context, report *models.Report) (uint64, error) {
+	if !isReportReason(report.Reason) {
+		return 0, fmt.Errorf("usecase check report reason %q: %w", report.Reason, ErrInvalidReportReason)
+	}
+
+	if len(report.Comment) > maxReportCommentLen {
+		return 0, fmt.Errorf("usecase check report comment length: %w", ErrInvalidReportComment)
+	}
+
+	if _, err := s.ds.GetMessage(ctx, report.BoardID, report.MessageID); err != nil {
+		return 0, fmt.Errorf("usecase is message exists: %w", err)
+	}
+
+	reportID, err := s.ds.PostReport(ctx, report)
+	if err != nil {
+		return 0, fmt.Errorf("usecase post report: %w", err)
+	}
+
+	return reportID, nil
+}
+
+func (s *Usecase) GetReportList(ctx context.Context, status int) (models.ReportList, error) {
+	reports, err := s.ds.GetReportList(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("usecase get reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetReportGroupList groups reports by the reported post, keeping the order
+// in which posts were first reported.
+func (s *Usecase) GetReportGroupList(ctx context.Context, status int) (models.ReportGroupList, error) {
+	reports, err := s.ds.GetReportList(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("usecase get reports for grouping: %w", err)
+	}
+
+	groups := models.ReportGroupList{}
+	index := make(map[uint64]int)
+
+	for _, report := range reports {
+		i, ok := index[report.MessageID]
+		if !ok {
+			i = len(groups)
+			index[report.MessageID] = i
+
+			groups = append(groups, models.ReportGroup{
+				BoardID:   report.BoardID,
+				MessageID: report.MessageID,
+			})
+		}
+
+		groups[i].Reports = append(groups[i].Reports, report)
+	}
+
+	return groups, nil
+}
+
+func (s *Usecase) DismissReport(ctx context.Context, reportID uint64) error {
+	if _, err := s.openReport(ctx, reportID); err != nil {
+		return err
+	}
+
+	if err := s.ds.ResolveReport(ctx, reportID, models.ReportDismissed); err != nil {
+		return fmt.Errorf("usecase dismiss report: %w", resolved(err))
+	}
+
+	return nil
+}
+
+// ActOnReport removes the reported post and resolves every open report
+// filed against it.
+func (s *Usecase) ActOnReport(ctx context.Context, reportID uint64) error {
+	report, err := s.openReport(ctx, reportID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ds.ActOnReport(ctx, report); err != nil {
+		return fmt.Errorf("usecase act on report: %w", resolved(err))
+	}
+
+	return nil
+}
+
+func (s *Usecase) openReport(ctx context.Context, reportID uint64) (*models.Report, error) {
+	report, err := s.ds.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("usecase get report: %w", err)
+	}
+
+	if report.Status != models.ReportOpen {
+		return nil, fmt.Errorf("usecase check report %d status: %w", reportID, ErrReportResolved)
+	}
+
+	return report, nil
+}
+
+// resolved reports a report resolved by someone else since openReport as
+// already resolved.
+func resolved(err error) error {
+	if errors.Is(err, database.ErrConflict) {
+		return ErrReportResolved
+	}
+
+	return err
+}
@@ -0,0 +1,285 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Batyachelly/goBoard/generated/mocks"
+	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUsecase_PostReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		report  *models.Report
+		ds      database.Databaser
+		want    uint64
+		wantErr error
+	}{
+		{
+			name: "1",
+			report: &models.Report{
+				BoardID:   101,
+				MessageID: 202,
+				Reason:    models.ReportReasonSpam,
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetMessage", mock.Anything, uint64(101), uint64(202)).Once().Return(&models.Message{}, nil)
+				ds.On("PostReport", mock.Anything, &models.Report{
+					BoardID:   101,
+					MessageID: 202,
+					Reason:    models.ReportReasonSpam,
+				}).Once().Return(uint64(303), nil)
+
+				return ds
+			}(),
+			want: 303,
+		},
+		{
+			name: "2 error, unknown reason",
+			report: &models.Report{
+				BoardID:   101,
+				MessageID: 202,
+				Reason:    "boring",
+			},
+			ds:      &mocks.Databaser{},
+			wantErr: usecase.ErrInvalidReportReason,
+		},
+		{
+			name: "3 error, message not found",
+			report: &models.Report{
+				BoardID:   101,
+				MessageID: 202,
+				Reason:    models.ReportReasonIllegal,
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetMessage", mock.Anything, uint64(101), uint64(202)).Once().Return(nil, database.ErrNotFound)
+
+				return ds
+			}(),
+			wantErr: database.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
+			got, err := s.PostReport(context.Background(), tt.report)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.PostReport() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("Usecase.PostReport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetReportGroupList(t *testing.T) {
+	t.Parallel()
+
+	ds := &mocks.Databaser{}
+	ds.On("GetReportList", mock.Anything, models.ReportOpen).Once().Return(models.ReportList{
+		{ID: 1, BoardID: 101, MessageID: 202},
+		{ID: 2, BoardID: 101, MessageID: 203},
+		{ID: 3, BoardID: 101, MessageID: 202},
+	}, nil)
+
+	got, err := usecase.NewUsecase(ds, usecase.Config{}).GetReportGroupList(context.Background(), models.ReportOpen)
+	if err != nil {
+		t.Fatalf("Usecase.GetReportGroupList() error = %v", err)
+	}
+
+	assert.Equal(t, models.ReportGroupList{
+		{
+			BoardID:   101,
+			MessageID: 202,
+			Reports: models.ReportList{
+				{ID: 1, BoardID: 101, MessageID: 202},
+				{ID: 3, BoardID: 101, MessageID: 202},
+			},
+		},
+		{
+			BoardID:   101,
+			MessageID: 203,
+			Reports: models.ReportList{
+				{ID: 2, BoardID: 101, MessageID: 203},
+			},
+		},
+	}, got)
+}
+
+func TestUsecase_ActOnReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		reportID uint64
+		ds       *mocks.Databaser
+		wantErr  error
+	}{
+		{
+			name:     "1",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(&models.Report{
+					ID:        1,
+					BoardID:   101,
+					MessageID: 202,
+					Status:    models.ReportOpen,
+				}, nil)
+				ds.On("ActOnReport", mock.Anything, &models.Report{
+					ID:        1,
+					BoardID:   101,
+					MessageID: 202,
+					Status:    models.ReportOpen,
+				}).Once().Return(nil)
+
+				return ds
+			}(),
+		},
+		{
+			name:     "2 error, already resolved",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(&models.Report{
+					ID:     1,
+					Status: models.ReportDismissed,
+				}, nil)
+
+				return ds
+			}(),
+			wantErr: usecase.ErrReportResolved,
+		},
+		{
+			name:     "3 error, resolved meanwhile",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(&models.Report{
+					ID:     1,
+					Status: models.ReportOpen,
+				}, nil)
+				ds.On("ActOnReport", mock.Anything, mock.Anything).Once().Return(database.ErrConflict)
+
+				return ds
+			}(),
+			wantErr: usecase.ErrReportResolved,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
+			err := s.ActOnReport(context.Background(), tt.reportID)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.ActOnReport() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			tt.ds.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsecase_DismissReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		reportID uint64
+		ds       *mocks.Databaser
+		wantErr  error
+	}{
+		{
+			name:     "1",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(&models.Report{
+					ID:     1,
+					Status: models.ReportOpen,
+				}, nil)
+				ds.On("ResolveReport", mock.Anything, uint64(1), models.ReportDismissed).Once().Return(nil)
+
+				return ds
+			}(),
+		},
+		{
+			name:     "2 error, not found",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(nil, database.ErrNotFound)
+
+				return ds
+			}(),
+			wantErr: database.ErrNotFound,
+		},
+		{
+			name:     "3 error, already resolved",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(&models.Report{
+					ID:     1,
+					Status: models.ReportActioned,
+				}, nil)
+
+				return ds
+			}(),
+			wantErr: usecase.ErrReportResolved,
+		},
+		{
+			name:     "4 error, resolved meanwhile",
+			reportID: 1,
+			ds: func() *mocks.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetReport", mock.Anything, uint64(1)).Once().Return(&models.Report{
+					ID:     1,
+					Status: models.ReportOpen,
+				}, nil)
+				ds.On("ResolveReport", mock.Anything, uint64(1), models.ReportDismissed).Once().Return(database.ErrConflict)
+
+				return ds
+			}(),
+			wantErr: usecase.ErrReportResolved,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
+			err := s.DismissReport(context.Background(), tt.reportID)
+			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Usecase.DismissReport() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			tt.ds.AssertExpectations(t)
+		})
+	}
+}
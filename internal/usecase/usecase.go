@@ -3,18 +3,44 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Batyachelly/goBoard/internal/database"
 	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/privacy"
 )
 
 type Usecase struct {
 	ds database.Databaser
+
+	ips         *privacy.IPProtector
+	ipRetention time.Duration
+
+	deletionWindow time.Duration
+
+	posts *postWatch
+}
+
+type Config struct {
+	// IPProtector is used to store poster addresses, they are not stored at all when it is nil.
+	IPProtector *privacy.IPProtector
+	IPRetention time.Duration
+	// DeletionWindow is how long authors may delete their own posts, zero disables it.
+	DeletionWindow time.Duration
+	// MaxWaiters caps the GetThread calls waiting for messages at once, zero leaves them unlimited.
+	MaxWaiters int
 }
 
-func NewUsecase(ds database.Databaser) *Usecase {
+func NewUsecase(ds database.Databaser, cfg Config) *Usecase {
 	return &Usecase{
 		ds: ds,
+
+		ips:         cfg.IPProtector,
+		ipRetention: cfg.IPRetention,
+
+		deletionWindow: cfg.DeletionWindow,
+
+		posts: newPostWatch(cfg.MaxWaiters),
 	}
 }
 
@@ -33,16 +59,102 @@ func (s *Usecase) GetBoard(ctx context.Context, boardID uint64) (*models.Board,
 		return nil, fmt.Errorf("usecase get board: %w", err)
 	}
 
+	if err := s.hideSage(ctx, boardID, board.Threads); err != nil {
+		return nil, err
+	}
+
 	return board, nil
 }
 
-func (s *Usecase) GetThread(ctx context.Context, boardID, threadID uint64) (models.MessageList, error) {
+// waitRecheck is how often a waiting GetThread queries again, to see the
+// messages posted through other processes.
+const waitRecheck = 5 * time.Second
+
+// GetThread returns the messages of a thread matching the filter. With a
+// filter Wait and no matching messages yet, it holds until one is posted or
+// the wait is over, returning an empty list in the latter case. Waiting
+// needs the thread to exist and fails when too many calls wait already.
+func (s *Usecase) GetThread(ctx context.Context, boardID, threadID uint64, filter models.ThreadFilter) (models.MessageList, error) {
+	if filter.Wait <= 0 {
+		thread, _, err := s.readThread(ctx, boardID, threadID, filter)
+
+		return thread, err
+	}
+
+	if !s.posts.enter() {
+		return nil, fmt.Errorf("usecase wait for thread %d: %w", threadID, ErrTooManyWaiters)
+	}
+	defer s.posts.leave()
+
+	timeout := time.NewTimer(filter.Wait)
+	defer timeout.Stop()
+
+	recheck := time.NewTicker(waitRecheck)
+	defer recheck.Stop()
+
+	for {
+		// Watch before reading, so a message posted right after the read is not missed.
+		posted := s.posts.watch(boardID, threadID)
+
+		thread, found, err := s.readThread(ctx, boardID, threadID, filter)
+		if err == nil && !found {
+			err = fmt.Errorf("usecase wait for thread %d: %w", threadID, database.ErrNotFound)
+		}
+
+		if err != nil || len(thread) > 0 {
+			s.posts.release(boardID, threadID, posted)
+
+			return thread, err
+		}
+
+		select {
+		case <-posted:
+		case <-recheck.C:
+		case <-timeout.C:
+			s.posts.release(boardID, threadID, posted)
+
+			return thread, nil
+		case <-ctx.Done():
+			s.posts.release(boardID, threadID, posted)
+
+			return thread, nil
+		}
+
+		s.posts.release(boardID, threadID, posted)
+	}
+}
+
+// readThread returns the filtered messages of a thread and whether it has
+// any messages at all, as the backends list none for a missing thread.
+func (s *Usecase) readThread(ctx context.Context, boardID, threadID uint64, filter models.ThreadFilter) (models.MessageList, bool, error) {
 	thread, err := s.ds.GetThread(ctx, boardID, threadID)
 	if err != nil {
-		return nil, fmt.Errorf("usecase get thread: %w", err)
+		return nil, false, fmt.Errorf("usecase get thread: %w", err)
+	}
+
+	if err := s.hideSage(ctx, boardID, thread); err != nil {
+		return nil, false, err
 	}
 
-	return thread, nil
+	if filter.PosterID == "" && filter.Since == 0 {
+		return thread, len(thread) > 0, nil
+	}
+
+	filtered := models.MessageList{}
+
+	for _, message := range thread {
+		if filter.PosterID != "" && message.PosterID != filter.PosterID {
+			continue
+		}
+
+		if message.ID <= filter.Since {
+			continue
+		}
+
+		filtered = append(filtered, message)
+	}
+
+	return filtered, len(thread) > 0, nil
 }
 
 func (s *Usecase) PostThread(ctx context.Context, thread *models.Message) (uint64, error) {
@@ -50,7 +162,31 @@ func (s *Usecase) PostThread(ctx context.Context, thread *models.Message) (uint6
 		return 0, fmt.Errorf("usecase is board exists: %w", err)
 	}
 
-	_, threadID, err := s.ds.PostThread(ctx, thread)
+	posterIDs, err := s.posterIDsEnabled(ctx, thread)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.protectIP(thread); err != nil {
+		return 0, err
+	}
+
+	if err := s.protectDeletionPassword(thread); err != nil {
+		return 0, err
+	}
+
+	// The thread ID is only known once the opening post is stored.
+	var posterID database.PosterIDFunc
+
+	if posterIDs {
+		ip := thread.IP
+
+		posterID = func(threadID uint64) (string, error) {
+			return s.ips.PosterID(ip, threadID, time.Now())
+		}
+	}
+
+	_, threadID, err := s.ds.PostThread(ctx, thread, posterID)
 	if err != nil {
 		return 0, fmt.Errorf("usecase post thread: %w", err)
 	}
@@ -59,14 +195,75 @@ func (s *Usecase) PostThread(ctx context.Context, thread *models.Message) (uint6
 }
 
 func (s *Usecase) PostMessage(ctx context.Context, message *models.Message) (uint64, error) {
-	if _, err := s.ds.GetThread(ctx, message.BoardID, message.ThreadID); err != nil {
+	// The backends list no messages for a missing thread.
+	thread, err := s.ds.GetThread(ctx, message.BoardID, message.ThreadID)
+	if err == nil && len(thread) == 0 {
+		err = database.ErrNotFound
+	}
+
+	if err != nil {
 		return 0, fmt.Errorf("usecase is thread exists: %w", err)
 	}
 
+	posterIDs, err := s.posterIDsEnabled(ctx, message)
+	if err != nil {
+		return 0, err
+	}
+
+	if posterIDs {
+		if err := s.assignPosterID(message); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.protectIP(message); err != nil {
+		return 0, err
+	}
+
+	if err := s.protectDeletionPassword(message); err != nil {
+		return 0, err
+	}
+
 	messageID, err := s.ds.PostMessage(ctx, message)
 	if err != nil {
 		return 0, fmt.Errorf("usecase post comment: %w", err)
 	}
 
+	s.posts.notify(message.BoardID, message.ThreadID)
+
+	if !message.Sage {
+		if err := s.ds.BumpThread(ctx, message.BoardID, message.ThreadID); err != nil {
+			return 0, fmt.Errorf("usecase bump thread: %w", err)
+		}
+	}
+
 	return messageID, nil
 }
+
+// hideSage clears the sage flags of a thread on boards that do not show them.
+func (s *Usecase) hideSage(ctx context.Context, boardID uint64, thread models.MessageList) error {
+	hasSage := false
+
+	for _, message := range thread {
+		hasSage = hasSage || message.Sage
+	}
+
+	if !hasSage {
+		return nil
+	}
+
+	settings, err := s.ds.GetBoardSettings(ctx, boardID)
+	if err != nil {
+		return fmt.Errorf("usecase get board settings for sage: %w", err)
+	}
+
+	if settings.ShowSage {
+		return nil
+	}
+
+	for i := range thread {
+		thread[i].Sage = false
+	}
+
+	return nil
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Batyachelly/goBoard/generated/mocks"
 	"github.com/Batyachelly/goBoard/internal/database"
+	"github.com/Batyachelly/goBoard/internal/database/memory"
 	"github.com/Batyachelly/goBoard/internal/database/models"
+	"github.com/Batyachelly/goBoard/internal/privacy"
 	"github.com/Batyachelly/goBoard/internal/usecase"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUsecase_GetBoardList(t *testing.T) {
@@ -79,7 +84,7 @@ func TestUsecase_GetBoardList(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			s := usecase.NewUsecase(tt.ds)
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
 			got, err := s.GetBoardList(tt.args.ctx)
 			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
 				t.Errorf("Usecase.GetBoardList() error = %v, wantErr %v", err, tt.wantErr)
@@ -156,6 +161,52 @@ func TestUsecase_GetBoard(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "sage hidden",
+			args: args{
+				ctx:     context.Background(),
+				boardID: 101,
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetBoard", mock.Anything, uint64(101)).Once().Return(&models.Board{
+					ID:      101,
+					Title:   "TestBoard",
+					Threads: models.MessageList{{ID: 1, Title: "Title1", Sage: true}},
+				}, nil)
+				ds.On("GetBoardSettings", mock.Anything, uint64(101)).Once().Return(&models.BoardSettings{ShowSage: false}, nil)
+
+				return ds
+			}(),
+			want: &models.Board{
+				ID:      101,
+				Title:   "TestBoard",
+				Threads: models.MessageList{{ID: 1, Title: "Title1"}},
+			},
+		},
+		{
+			name: "sage shown",
+			args: args{
+				ctx:     context.Background(),
+				boardID: 101,
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetBoard", mock.Anything, uint64(101)).Once().Return(&models.Board{
+					ID:      101,
+					Title:   "TestBoard",
+					Threads: models.MessageList{{ID: 1, Title: "Title1", Sage: true}},
+				}, nil)
+				ds.On("GetBoardSettings", mock.Anything, uint64(101)).Once().Return(&models.BoardSettings{ShowSage: true}, nil)
+
+				return ds
+			}(),
+			want: &models.Board{
+				ID:      101,
+				Title:   "TestBoard",
+				Threads: models.MessageList{{ID: 1, Title: "Title1", Sage: true}},
+			},
+		},
 		{
 			name: "2 error, thread not found",
 			args: args{
@@ -176,7 +227,7 @@ func TestUsecase_GetBoard(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			s := usecase.NewUsecase(tt.ds)
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
 			got, err := s.GetBoard(tt.args.ctx, tt.args.boardID)
 			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
 				t.Errorf("Usecase.GetBoard() error = %v, wantErr %v", err, tt.wantErr)
@@ -198,6 +249,7 @@ func TestUsecase_GetThread(t *testing.T) {
 		ctx      context.Context
 		boardID  uint64
 		threadID uint64
+		filter   models.ThreadFilter
 	}
 	tests := []struct {
 		name    string
@@ -272,14 +324,80 @@ func TestUsecase_GetThread(t *testing.T) {
 			}(),
 			wantErr: sql.ErrNoRows,
 		},
+		{
+			name: "3 filter by poster id",
+			args: args{
+				ctx:      context.Background(),
+				boardID:  101,
+				threadID: 202,
+				filter:   models.ThreadFilter{PosterID: "a1b2c3d4"},
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{
+					{ID: 1, PosterID: "a1b2c3d4"},
+					{ID: 2, PosterID: "ffff0000"},
+					{ID: 3, PosterID: "a1b2c3d4"},
+					{ID: 4},
+				}, nil)
+
+				return ds
+			}(),
+			want: models.MessageList{
+				{ID: 1, PosterID: "a1b2c3d4"},
+				{ID: 3, PosterID: "a1b2c3d4"},
+			},
+		},
+		{
+			name: "4 since message",
+			args: args{
+				ctx:      context.Background(),
+				boardID:  101,
+				threadID: 202,
+				filter:   models.ThreadFilter{Since: 2, PosterID: "a1b2c3d4"},
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{
+					{ID: 1, PosterID: "a1b2c3d4"},
+					{ID: 2, PosterID: "a1b2c3d4"},
+					{ID: 3, PosterID: "ffff0000"},
+					{ID: 4, PosterID: "a1b2c3d4"},
+				}, nil)
+
+				return ds
+			}(),
+			want: models.MessageList{
+				{ID: 4, PosterID: "a1b2c3d4"},
+			},
+		},
+		{
+			name: "5 wait without new messages",
+			args: args{
+				ctx:      context.Background(),
+				boardID:  101,
+				threadID: 202,
+				filter:   models.ThreadFilter{Since: 2, Wait: 10 * time.Millisecond},
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{
+					{ID: 1},
+					{ID: 2},
+				}, nil)
+
+				return ds
+			}(),
+			want: models.MessageList{},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			s := usecase.NewUsecase(tt.ds)
-			got, err := s.GetThread(tt.args.ctx, tt.args.boardID, tt.args.threadID)
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
+			got, err := s.GetThread(tt.args.ctx, tt.args.boardID, tt.args.threadID, tt.args.filter)
 			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
 				t.Errorf("Usecase.GetThread() error = %v, wantErr %v", err, tt.wantErr)
 
@@ -326,7 +444,7 @@ func TestUsecase_PostThread(t *testing.T) {
 					Title:   "Title",
 					Text:    "Text",
 					Content: "Content",
-				}).Once().Return(uint64(1), uint64(303), nil)
+				}, mock.MatchedBy(func(f database.PosterIDFunc) bool { return f == nil })).Once().Return(uint64(1), uint64(303), nil)
 
 				return ds
 			}(),
@@ -352,7 +470,7 @@ func TestUsecase_PostThread(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			s := usecase.NewUsecase(tt.ds)
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
 			got, err := s.PostThread(tt.args.ctx, tt.args.thread)
 			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
 				t.Errorf("Usecase.PostThread() error = %v, wantErr %v", err, tt.wantErr)
@@ -395,7 +513,7 @@ func TestUsecase_PostComment(t *testing.T) {
 			},
 			ds: func() database.Databaser {
 				ds := &mocks.Databaser{}
-				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{}, nil)
+				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{{ID: 202}}, nil)
 				ds.On("PostMessage", mock.Anything, &models.Message{
 					BoardID:  101,
 					ThreadID: 202,
@@ -403,13 +521,39 @@ func TestUsecase_PostComment(t *testing.T) {
 					Text:     "Text",
 					Content:  "Content",
 				}).Once().Return(uint64(303), nil)
+				ds.On("BumpThread", mock.Anything, uint64(101), uint64(202)).Once().Return(nil)
 
 				return ds
 			}(),
 			want: 303,
 		},
 		{
-			name: "2 error, thread not found",
+			name: "2 sage",
+			args: args{
+				ctx: context.Background(),
+				comment: &models.Message{
+					BoardID:  101,
+					ThreadID: 202,
+					Text:     "Text",
+					Sage:     true,
+				},
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{{ID: 202}}, nil)
+				ds.On("PostMessage", mock.Anything, &models.Message{
+					BoardID:  101,
+					ThreadID: 202,
+					Text:     "Text",
+					Sage:     true,
+				}).Once().Return(uint64(304), nil)
+
+				return ds
+			}(),
+			want: 304,
+		},
+		{
+			name: "3 error, thread not found",
 			args: args{
 				ctx: context.Background(),
 				comment: &models.Message{
@@ -425,13 +569,30 @@ func TestUsecase_PostComment(t *testing.T) {
 			}(),
 			wantErr: sql.ErrNoRows,
 		},
+		{
+			name: "4 error, thread without messages",
+			args: args{
+				ctx: context.Background(),
+				comment: &models.Message{
+					BoardID:  101,
+					ThreadID: 202,
+				},
+			},
+			ds: func() database.Databaser {
+				ds := &mocks.Databaser{}
+				ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{}, nil)
+
+				return ds
+			}(),
+			wantErr: database.ErrNotFound,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			s := usecase.NewUsecase(tt.ds)
+			s := usecase.NewUsecase(tt.ds, usecase.Config{})
 			got, err := s.PostMessage(tt.args.ctx, tt.args.comment)
 			if (err != nil || tt.wantErr != nil) && !errors.Is(err, tt.wantErr) {
 				t.Errorf("Usecase.PostMessage() error = %v, wantErr %v", err, tt.wantErr)
@@ -445,3 +606,152 @@ func TestUsecase_PostComment(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_GetThread_Wait(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+	s := usecase.NewUsecase(ds, usecase.Config{})
+
+	threadID, err := s.PostThread(ctx, &models.Message{BoardID: 1, Title: "OP"})
+	require.NoError(t, err)
+
+	type result struct {
+		thread models.MessageList
+		err    error
+	}
+
+	done := make(chan result)
+
+	go func() {
+		thread, err := s.GetThread(ctx, 1, threadID, models.ThreadFilter{Since: 1, Wait: time.Minute})
+		done <- result{thread: thread, err: err}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("GetThread returned before a reply")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	replyID, err := s.PostMessage(ctx, &models.Message{BoardID: 1, ThreadID: threadID, Text: "Reply"})
+	require.NoError(t, err)
+
+	select {
+	case res := <-done:
+		require.NoError(t, res.err)
+		require.Len(t, res.thread, 1)
+		require.Equal(t, replyID, res.thread[0].ID)
+	case <-time.After(time.Second):
+		t.Fatal("GetThread was not woken by the reply")
+	}
+}
+
+func TestUsecase_GetThread_WaitMissing(t *testing.T) {
+	t.Parallel()
+
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+	s := usecase.NewUsecase(ds, usecase.Config{})
+
+	_, err := s.GetThread(context.Background(), 1, 1, models.ThreadFilter{Wait: time.Minute})
+	require.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestUsecase_GetThread_MaxWaiters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ds := memory.NewDatabaseService(memory.Config{Boards: []string{"General"}})
+	s := usecase.NewUsecase(ds, usecase.Config{MaxWaiters: 1})
+
+	threadID, err := s.PostThread(ctx, &models.Message{BoardID: 1, Title: "OP"})
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := s.GetThread(waitCtx, 1, threadID, models.ThreadFilter{Since: 1, Wait: time.Minute})
+		done <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := s.GetThread(ctx, 1, threadID, models.ThreadFilter{Since: 1, Wait: time.Millisecond})
+
+		return errors.Is(err, usecase.ErrTooManyWaiters)
+	}, time.Second, time.Millisecond, "a second waiter is turned away")
+
+	_, err = s.GetThread(ctx, 1, threadID, models.ThreadFilter{Since: 1})
+	require.NoError(t, err, "reads without a wait are not limited")
+
+	cancel()
+	require.NoError(t, <-done)
+
+	_, err = s.GetThread(ctx, 1, threadID, models.ThreadFilter{Since: 1, Wait: time.Millisecond})
+	require.NoError(t, err, "the slot is freed when the waiter leaves")
+}
+
+func TestUsecase_PosterIDs(t *testing.T) {
+	t.Parallel()
+
+	ips, err := privacy.NewIPProtector(privacy.Config{
+		HashKey:       "secret",
+		EncryptionKey: strings.Repeat("ab", 32),
+	})
+	require.NoError(t, err)
+
+	const ip = "198.51.100.7"
+
+	posterID := func(threadID uint64) string {
+		id, err := ips.PosterID(ip, threadID, time.Now())
+		require.NoError(t, err)
+
+		return id
+	}
+
+	for _, enabled := range []bool{true, false} {
+		enabled := enabled
+
+		t.Run(fmt.Sprintf("poster ids %t", enabled), func(t *testing.T) {
+			t.Parallel()
+
+			var threadPosterID, messagePosterID string
+
+			ds := &mocks.Databaser{}
+			ds.On("GetBoard", mock.Anything, uint64(101)).Once().Return(&models.Board{}, nil)
+			ds.On("GetBoardSettings", mock.Anything, uint64(101)).Twice().Return(&models.BoardSettings{PosterIDs: enabled}, nil)
+			ds.On("PostThread", mock.Anything, mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+				if f := args.Get(2).(database.PosterIDFunc); f != nil {
+					id, err := f(202)
+					require.NoError(t, err)
+
+					threadPosterID = id
+				}
+			}).Return(uint64(202), uint64(202), nil)
+			ds.On("GetThread", mock.Anything, uint64(101), uint64(202)).Once().Return(models.MessageList{{ID: 202}}, nil)
+			ds.On("PostMessage", mock.Anything, mock.Anything).Once().Run(func(args mock.Arguments) {
+				messagePosterID = args.Get(1).(*models.Message).PosterID
+			}).Return(uint64(203), nil)
+			ds.On("BumpThread", mock.Anything, uint64(101), uint64(202)).Once().Return(nil)
+
+			s := usecase.NewUsecase(ds, usecase.Config{IPProtector: ips})
+
+			threadID, err := s.PostThread(context.Background(), &models.Message{BoardID: 101, IP: ip})
+			require.NoError(t, err)
+
+			_, err = s.PostMessage(context.Background(), &models.Message{BoardID: 101, ThreadID: threadID, IP: ip})
+			require.NoError(t, err)
+
+			if enabled {
+				require.Equal(t, posterID(202), threadPosterID)
+				require.Equal(t, threadPosterID, messagePosterID, "the poster keeps the ID in the thread")
+			} else {
+				require.Empty(t, threadPosterID)
+				require.Empty(t, messagePosterID)
+			}
+
+			ds.AssertExpectations(t)
+		})
+	}
+}
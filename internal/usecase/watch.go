@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type threadKey struct {
+	boardID  uint64
+	threadID uint64
+}
+
+// postWatch wakes the callers waiting for a new message in a thread. It only
+// sees messages posted through this process.
+type postWatch struct {
+	mu      sync.Mutex
+	threads map[threadKey]*watchers
+
+	// waiting counts the callers between enter and leave, up to limit.
+	waiting int64
+	limit   int64
+}
+
+type watchers struct {
+	posted chan struct{}
+	count  int
+}
+
+func newPostWatch(limit int) *postWatch {
+	return &postWatch{threads: make(map[threadKey]*watchers), limit: int64(limit)}
+}
+
+// enter admits a caller about to wait, unless the limit is reached. Every
+// admitted caller must call leave when done.
+func (p *postWatch) enter() bool {
+	if atomic.AddInt64(&p.waiting, 1) > p.limit && p.limit > 0 {
+		atomic.AddInt64(&p.waiting, -1)
+
+		return false
+	}
+
+	return true
+}
+
+func (p *postWatch) leave() {
+	atomic.AddInt64(&p.waiting, -1)
+}
+
+// watch returns a channel closed on the next message posted to the thread.
+// Every call must be followed by one call of release.
+func (p *postWatch) watch(boardID, threadID uint64) <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := threadKey{boardID: boardID, threadID: threadID}
+
+	w, ok := p.threads[k]
+	if !ok {
+		w = &watchers{posted: make(chan struct{})}
+		p.threads[k] = w
+	}
+
+	w.count++
+
+	return w.posted
+}
+
+func (p *postWatch) release(boardID, threadID uint64, posted <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := threadKey{boardID: boardID, threadID: threadID}
+
+	// The watchers were replaced if a message was posted meanwhile.
+	w, ok := p.threads[k]
+	if !ok || w.posted != posted {
+		return
+	}
+
+	w.count--
+	if w.count == 0 {
+		delete(p.threads, k)
+	}
+}
+
+func (p *postWatch) notify(boardID, threadID uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := threadKey{boardID: boardID, threadID: threadID}
+
+	if w, ok := p.threads[k]; ok {
+		close(w.posted)
+		delete(p.threads, k)
+	}
+}
@@ -1,7 +1,7 @@
 # curl -XPOST -H "Content-Type:application/json" -d \
-# '{"boardId": 1, "title": "some title2", "text":"Hello"  }' \
-#  localhost:8080/thread
+# '{"title": "some title2", "text":"Hello"  }' \
+#  localhost:8080/api/v1/board/1/thread
 
 curl -XPOST -H "Content-Type:application/json" -d \
-'{"threadID": 1, "title": "some title2", "text":"Hello"  }' \
- localhost:8080/comment
+'{"title": "some title2", "text":"Hello"  }' \
+ localhost:8080/api/v1/board/1/thread/1/comment
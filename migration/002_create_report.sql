@@ -0,0 +1,16 @@
+CREATE TABLE report (
+    id serial PRIMARY KEY,
+    board_id INT NOT NULL,
+    message_id INT NOT NULL,
+    reason VARCHAR (32) NOT NULL,
+    comment VARCHAR (1024) NOT NULL,
+    status INT NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    resolved TIMESTAMP,
+    FOREIGN KEY (board_id) REFERENCES board (id),
+    FOREIGN KEY (message_id) REFERENCES message (id)
+);
+
+CREATE INDEX report_status_message_idx ON report (status, message_id);
+---- create above / drop below ----
+DROP TABLE report;
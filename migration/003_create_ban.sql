@@ -0,0 +1,15 @@
+CREATE TABLE ban (
+    id serial PRIMARY KEY,
+    board_id INT,
+    status INT NOT NULL,
+    network CIDR NOT NULL,
+    reason VARCHAR (1024) NOT NULL,
+    moderator VARCHAR (255) NOT NULL,
+    created TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires TIMESTAMPTZ,
+    FOREIGN KEY (board_id) REFERENCES board (id)
+);
+
+CREATE INDEX ban_network_idx ON ban USING gist (network inet_ops);
+---- create above / drop below ----
+DROP TABLE ban;
@@ -0,0 +1,20 @@
+ALTER TABLE message ADD COLUMN ip_hash VARCHAR (64);
+ALTER TABLE message ADD COLUMN ip_encrypted BYTEA;
+
+CREATE INDEX message_ip_retention_idx ON message (created) WHERE ip_encrypted IS NOT NULL;
+
+ALTER TABLE ban ALTER COLUMN network DROP NOT NULL;
+ALTER TABLE ban ADD COLUMN ip_hash VARCHAR (64);
+ALTER TABLE ban ADD CONSTRAINT ban_target_check CHECK (network IS NOT NULL OR ip_hash IS NOT NULL);
+
+CREATE INDEX ban_ip_hash_idx ON ban (ip_hash);
+---- create above / drop below ----
+DROP INDEX ban_ip_hash_idx;
+ALTER TABLE ban DROP CONSTRAINT ban_target_check;
+ALTER TABLE ban DROP COLUMN ip_hash;
+DELETE FROM ban WHERE network IS NULL;
+ALTER TABLE ban ALTER COLUMN network SET NOT NULL;
+
+DROP INDEX message_ip_retention_idx;
+ALTER TABLE message DROP COLUMN ip_encrypted;
+ALTER TABLE message DROP COLUMN ip_hash;
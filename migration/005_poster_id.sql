@@ -0,0 +1,5 @@
+ALTER TABLE board ADD COLUMN poster_ids BOOLEAN DEFAULT FALSE NOT NULL;
+ALTER TABLE message ADD COLUMN poster_id VARCHAR (16);
+---- create above / drop below ----
+ALTER TABLE message DROP COLUMN poster_id;
+ALTER TABLE board DROP COLUMN poster_ids;
@@ -0,0 +1,3 @@
+ALTER TABLE message ADD COLUMN deletion_hash VARCHAR (72);
+---- create above / drop below ----
+ALTER TABLE message DROP COLUMN deletion_hash;
@@ -0,0 +1,14 @@
+ALTER TABLE board ADD COLUMN show_sage BOOLEAN DEFAULT TRUE NOT NULL;
+ALTER TABLE message ADD COLUMN sage BOOLEAN DEFAULT FALSE NOT NULL;
+ALTER TABLE message ADD COLUMN bumped TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL;
+
+UPDATE message m SET bumped = t.last FROM (
+    SELECT thread_id, max(created) AS last FROM message GROUP BY thread_id
+) t WHERE m.thread_id = t.thread_id;
+
+CREATE INDEX message_board_bumped_idx ON message (board_id, bumped DESC);
+---- create above / drop below ----
+DROP INDEX message_board_bumped_idx;
+ALTER TABLE message DROP COLUMN bumped;
+ALTER TABLE message DROP COLUMN sage;
+ALTER TABLE board DROP COLUMN show_sage;
@@ -0,0 +1,15 @@
+-- updated is the last change to anything shown by the board or the thread,
+-- kept on every message of the thread like bumped.
+ALTER TABLE board ADD COLUMN updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL;
+ALTER TABLE message ADD COLUMN updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL;
+
+UPDATE message m SET updated = t.last FROM (
+    SELECT thread_id, max(created) AS last FROM message GROUP BY thread_id
+) t WHERE m.thread_id = t.thread_id;
+
+UPDATE board b SET updated = m.last FROM (
+    SELECT board_id, max(updated) AS last FROM message GROUP BY board_id
+) m WHERE b.id = m.board_id;
+---- create above / drop below ----
+ALTER TABLE message DROP COLUMN updated;
+ALTER TABLE board DROP COLUMN updated;
@@ -0,0 +1,15 @@
+// Package migration embeds the tern migrations of the Postgres schema and
+// the migrations of the SQLite schema, written in the same format.
+package migration
+
+import "embed"
+
+// FS holds the numbered migration files at its root.
+//
+//go:embed *.sql
+var FS embed.FS
+
+// SQLite holds the numbered SQLite migration files in the sqlite directory.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
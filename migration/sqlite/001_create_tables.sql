@@ -0,0 +1,76 @@
+CREATE TABLE board (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    status INTEGER NOT NULL,
+    title VARCHAR (255) NOT NULL,
+    poster_ids BOOLEAN DEFAULT FALSE NOT NULL,
+    show_sage BOOLEAN DEFAULT TRUE NOT NULL
+);
+
+-- thread hands out thread IDs, which advance independently of message IDs
+-- like the serial thread_id column in Postgres.
+CREATE TABLE thread (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES board (id)
+);
+
+CREATE TABLE message (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    thread_id INTEGER NOT NULL,
+    board_id INTEGER NOT NULL,
+    status INTEGER NOT NULL,
+    title VARCHAR (255) NOT NULL,
+    text TEXT NOT NULL,
+    content VARCHAR (255) NOT NULL,
+    created TIMESTAMP NOT NULL,
+    bumped TIMESTAMP NOT NULL,
+    poster_id VARCHAR (16),
+    ip_hash VARCHAR (64),
+    ip_encrypted BLOB,
+    deletion_hash VARCHAR (72),
+    sage BOOLEAN DEFAULT FALSE NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES board (id)
+);
+
+CREATE INDEX message_board_bumped_idx ON message (board_id, bumped DESC);
+CREATE INDEX message_thread_idx ON message (board_id, thread_id);
+CREATE INDEX message_ip_retention_idx ON message (created) WHERE ip_encrypted IS NOT NULL;
+
+CREATE TABLE report (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER NOT NULL,
+    message_id INTEGER NOT NULL,
+    reason VARCHAR (32) NOT NULL,
+    comment VARCHAR (1024) NOT NULL,
+    status INTEGER NOT NULL,
+    created TIMESTAMP NOT NULL,
+    resolved TIMESTAMP,
+    FOREIGN KEY (board_id) REFERENCES board (id),
+    FOREIGN KEY (message_id) REFERENCES message (id)
+);
+
+CREATE INDEX report_status_message_idx ON report (status, message_id);
+
+CREATE TABLE ban (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    board_id INTEGER,
+    status INTEGER NOT NULL,
+    network VARCHAR (43),
+    ip_hash VARCHAR (64),
+    reason VARCHAR (1024) NOT NULL,
+    moderator VARCHAR (255) NOT NULL,
+    created TIMESTAMP NOT NULL,
+    expires TIMESTAMP,
+    FOREIGN KEY (board_id) REFERENCES board (id),
+    CONSTRAINT ban_target_check CHECK (network IS NOT NULL OR ip_hash IS NOT NULL)
+);
+
+CREATE INDEX ban_ip_hash_idx ON ban (ip_hash);
+
+---- create above / drop below ----
+
+DROP TABLE ban;
+DROP TABLE report;
+DROP TABLE message;
+DROP TABLE thread;
+DROP TABLE board;
@@ -0,0 +1,15 @@
+-- updated is the last change to anything shown by the board or the thread,
+-- kept on every message of the thread like bumped.
+ALTER TABLE board ADD COLUMN updated TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+ALTER TABLE message ADD COLUMN updated TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+
+UPDATE message SET updated = (
+    SELECT max(created) FROM message t WHERE t.thread_id = message.thread_id
+);
+
+UPDATE board SET updated = coalesce((
+    SELECT max(updated) FROM message WHERE message.board_id = board.id
+), updated);
+---- create above / drop below ----
+ALTER TABLE message DROP COLUMN updated;
+ALTER TABLE board DROP COLUMN updated;
@@ -0,0 +1,304 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Batyachelly/goBoard/pkg/data"
+)
+
+// Report statuses to filter the reports by.
+const (
+	ReportOpen      = "open"
+	ReportDismissed = "dismissed"
+	ReportActioned  = "actioned"
+)
+
+// ThreadOptions filter the messages of a thread.
+type ThreadOptions struct {
+	// PosterID keeps only the messages of one poster.
+	PosterID string
+	// Since keeps only the messages after this message ID.
+	Since uint64
+	// Wait is how long the server waits for a message when there are none
+	// yet, capped by the server.
+	Wait time.Duration
+}
+
+func (o ThreadOptions) query() url.Values {
+	query := url.Values{}
+
+	if o.PosterID != "" {
+		query.Set("poster_id", o.PosterID)
+	}
+
+	if o.Since != 0 {
+		query.Set("since", strconv.FormatUint(o.Since, 10))
+	}
+
+	if o.Wait > 0 {
+		query.Set("wait", o.Wait.String())
+	}
+
+	return query
+}
+
+// Healthz fails if the server is not live.
+func (c *Client) Healthz(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/healthz"}, nil)
+}
+
+// Readyz fails if the server is not ready to serve requests.
+func (c *Client) Readyz(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/readyz"}, nil)
+}
+
+func (c *Client) GetBoards(ctx context.Context) (data.GetBoardsResponse, error) {
+	var resp data.GetBoardsResponse
+
+	if err := c.do(ctx, request{method: http.MethodGet, path: apiPrefix + "/board"}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) GetBoard(ctx context.Context, boardID uint64) (*data.GetBoardResponse, error) {
+	resp := new(data.GetBoardResponse)
+
+	if err := c.do(ctx, request{method: http.MethodGet, path: boardPath(boardID)}, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) GetThread(ctx context.Context, boardID, threadID uint64, opts ThreadOptions) (data.GetThread, error) {
+	var resp data.GetThread
+
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   threadPath(boardID, threadID),
+		query:  opts.query(),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) PostThread(
+	ctx context.Context, boardID uint64, thread data.PostThreadRequest,
+) (*data.PostThreadResponse, error) {
+	resp := new(data.PostThreadResponse)
+
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   boardPath(boardID) + "/thread",
+		body:   thread,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) PostMessage(
+	ctx context.Context, boardID, threadID uint64, message data.PostMessageRequest,
+) (*data.PostMessageResponse, error) {
+	resp := new(data.PostMessageResponse)
+
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   threadPath(boardID, threadID) + "/comment",
+		body:   message,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) PostReport(
+	ctx context.Context, boardID, messageID uint64, report data.PostReportRequest,
+) (*data.PostReportResponse, error) {
+	resp := new(data.PostReportResponse)
+
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   messagePath(boardID, messageID) + "/report",
+		body:   report,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DeleteMessage(ctx context.Context, boardID, messageID uint64, req data.DeleteMessageRequest) error {
+	return c.do(ctx, request{
+		method: http.MethodPost,
+		path:   messagePath(boardID, messageID) + "/delete",
+		body:   req,
+	}, nil)
+}
+
+// GetReports lists the reports with the status, all open ones if it is empty.
+func (c *Client) GetReports(ctx context.Context, status string) (data.GetReportsResponse, error) {
+	var resp data.GetReportsResponse
+
+	err := c.do(ctx, request{
+		method:    http.MethodGet,
+		path:      apiPrefix + "/mod/report",
+		query:     statusQuery(status),
+		moderator: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetReportGroups is GetReports grouped by the reported message.
+func (c *Client) GetReportGroups(ctx context.Context, status string) (data.GetReportGroupsResponse, error) {
+	var resp data.GetReportGroupsResponse
+
+	err := c.do(ctx, request{
+		method:    http.MethodGet,
+		path:      apiPrefix + "/mod/report/post",
+		query:     statusQuery(status),
+		moderator: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DismissReport(ctx context.Context, reportID uint64) error {
+	return c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      reportPath(reportID) + "/dismiss",
+		moderator: true,
+	}, nil)
+}
+
+// ActOnReport deletes the reported message and resolves its reports.
+func (c *Client) ActOnReport(ctx context.Context, reportID uint64) error {
+	return c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      reportPath(reportID) + "/action",
+		moderator: true,
+	}, nil)
+}
+
+func (c *Client) GetBans(ctx context.Context) (data.GetBansResponse, error) {
+	var resp data.GetBansResponse
+
+	err := c.do(ctx, request{
+		method:    http.MethodGet,
+		path:      apiPrefix + "/mod/ban",
+		moderator: true,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) PostBan(ctx context.Context, ban data.PostBanRequest) (*data.PostBanResponse, error) {
+	resp := new(data.PostBanResponse)
+
+	err := c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      apiPrefix + "/mod/ban",
+		body:      ban,
+		moderator: true,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) DeleteBan(ctx context.Context, banID uint64) error {
+	return c.do(ctx, request{
+		method:    http.MethodDelete,
+		path:      fmt.Sprintf("%s/mod/ban/%d", apiPrefix, banID),
+		moderator: true,
+	}, nil)
+}
+
+// PostMessageBan bans the author of a message.
+func (c *Client) PostMessageBan(
+	ctx context.Context, boardID, messageID uint64, ban data.PostMessageBanRequest,
+) (*data.PostBanResponse, error) {
+	resp := new(data.PostBanResponse)
+
+	err := c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      modMessagePath(boardID, messageID) + "/ban",
+		body:      ban,
+		moderator: true,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) GetMessageAuthor(ctx context.Context, boardID, messageID uint64) (*data.MessageAuthorResponse, error) {
+	resp := new(data.MessageAuthorResponse)
+
+	err := c.do(ctx, request{
+		method:    http.MethodGet,
+		path:      modMessagePath(boardID, messageID) + "/author",
+		moderator: true,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func boardPath(boardID uint64) string {
+	return fmt.Sprintf("%s/board/%d", apiPrefix, boardID)
+}
+
+func threadPath(boardID, threadID uint64) string {
+	return fmt.Sprintf("%s/thread/%d", boardPath(boardID), threadID)
+}
+
+func messagePath(boardID, messageID uint64) string {
+	return fmt.Sprintf("%s/message/%d", boardPath(boardID), messageID)
+}
+
+func modMessagePath(boardID, messageID uint64) string {
+	return fmt.Sprintf("%s/mod/board/%d/message/%d", apiPrefix, boardID, messageID)
+}
+
+func reportPath(reportID uint64) string {
+	return fmt.Sprintf("%s/mod/report/%d", apiPrefix, reportID)
+}
+
+func statusQuery(status string) url.Values {
+	if status == "" {
+		return nil
+	}
+
+	return url.Values{"status": {status}}
+}
@@ -0,0 +1,332 @@
+// Package client is a Go client of the goBoard HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Batyachelly/goBoard/pkg/data"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+
+	apiPrefix = "/api/v1"
+)
+
+var errBaseURL = errors.New("base URL must be absolute")
+
+type Client struct {
+	baseURL        *url.URL
+	http           *http.Client
+	moderatorToken string
+
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type Config struct {
+	// BaseURL is the address the server is reachable at, without the API
+	// prefix, e.g. "https://board.example.com".
+	BaseURL    string
+	HTTPClient *http.Client
+	// ModeratorToken authenticates the moderation methods.
+	ModeratorToken string
+
+	// MaxRetries is how many times a failed request is retried, zero keeps
+	// DefaultMaxRetries and a negative value turns retries off.
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func New(cfg Config) (*Client, error) {
+	baseURL, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: parse base URL: %w", err)
+	}
+
+	if !baseURL.IsAbs() {
+		return nil, fmt.Errorf("client: %w", errBaseURL)
+	}
+
+	baseURL.Path = strings.TrimSuffix(baseURL.Path, "/")
+
+	c := &Client{
+		baseURL:        baseURL,
+		http:           cfg.HTTPClient,
+		moderatorToken: cfg.ModeratorToken,
+		maxRetries:     cfg.MaxRetries,
+		minBackoff:     cfg.MinBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+	}
+
+	if c.http == nil {
+		c.http = http.DefaultClient
+	}
+
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = DefaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+
+	if c.minBackoff <= 0 {
+		c.minBackoff = DefaultMinBackoff
+	}
+
+	if c.maxBackoff < c.minBackoff {
+		c.maxBackoff = DefaultMaxBackoff
+		if c.maxBackoff < c.minBackoff {
+			c.maxBackoff = c.minBackoff
+		}
+	}
+
+	return c, nil
+}
+
+// Error is a response other than 200 OK.
+type Error struct {
+	StatusCode int
+	// Code and Message explain the rejection of invalid requests.
+	Code    string
+	Message string
+	// Ban is the ban refusing a banned client.
+	Ban *data.BannedResponse
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("goboard: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+	switch {
+	case e.Message != "":
+		msg += ": " + e.Message
+	case e.Ban != nil:
+		msg += fmt.Sprintf(": ban #%d: %s", e.Ban.BanID, e.Ban.Reason)
+	}
+
+	return msg
+}
+
+// StatusCode is the status of the response err comes from, zero if it does
+// not come from one.
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+
+	return 0
+}
+
+type request struct {
+	method    string
+	path      string
+	query     url.Values
+	body      interface{}
+	moderator bool
+}
+
+// do sends the request, retrying it with backoff, and decodes the response
+// into out unless out is nil.
+//
+// A 429 is retried for any method, since the server rejects those before
+// handling them. Failed connections and 5xx responses are only retried for
+// the methods safe to repeat, so a post is never made twice.
+func (c *Client) do(ctx context.Context, r request, out interface{}) error {
+	var body []byte
+
+	if r.body != nil {
+		var err error
+
+		body, err = json.Marshal(r.body)
+		if err != nil {
+			return fmt.Errorf("%s %s: encode request: %w", r.method, r.path, err)
+		}
+	}
+
+	idempotent := r.method == http.MethodGet || r.method == http.MethodDelete
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, r, body)
+		if err != nil {
+			if ctx.Err() != nil || !idempotent || attempt >= c.maxRetries {
+				return fmt.Errorf("%s %s: %w", r.method, r.path, err)
+			}
+
+			if err := c.sleep(ctx, c.backoff(attempt)); err != nil {
+				return fmt.Errorf("%s %s: %w", r.method, r.path, err)
+			}
+
+			continue
+		}
+
+		retry := resp.StatusCode == http.StatusTooManyRequests ||
+			(idempotent && resp.StatusCode >= http.StatusInternalServerError)
+
+		if !retry || attempt >= c.maxRetries {
+			return decodeResponse(resp, r, out)
+		}
+
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			wait = c.backoff(attempt)
+		}
+
+		drain(resp.Body)
+
+		if err := c.sleep(ctx, wait); err != nil {
+			return fmt.Errorf("%s %s: %w", r.method, r.path, err)
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, r request, body []byte) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += r.path
+	u.RawQuery = r.query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if r.moderator {
+		req.Header.Set("Authorization", "Bearer "+c.moderatorToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	return resp, nil
+}
+
+func decodeResponse(resp *http.Response, r request, out interface{}) error {
+	defer drain(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: decode response: %w", r.method, r.path, err)
+	}
+
+	return nil
+}
+
+// responseError reads what the server said about the failure. Most
+// failures carry no body, rejected requests an ErrorResponse and refused
+// banned clients a BannedResponse.
+func responseError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return apiErr
+	}
+
+	if resp.StatusCode == http.StatusForbidden {
+		ban := new(data.BannedResponse)
+		if json.Unmarshal(body, ban) == nil && ban.BanID != 0 {
+			apiErr.Ban = ban
+
+			return apiErr
+		}
+	}
+
+	var errResp data.ErrorResponse
+	if json.Unmarshal(body, &errResp) == nil {
+		apiErr.Code = errResp.Code
+		apiErr.Message = errResp.Message
+	}
+
+	return apiErr
+}
+
+// backoff is the exponential delay before the retry after attempt, with
+// jitter so that clients failing together do not retry together.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.minBackoff
+	for i := 0; i < attempt && d < c.maxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+
+	half := d / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1)) //nolint:gosec
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as a date.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait to retry: %w", ctx.Err())
+	}
+}
+
+// drain reads the rest of the body so the connection can be reused.
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 1<<16))
+	body.Close()
+}
@@ -0,0 +1,428 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Batyachelly/goBoard/internal/database/memory"
+	"github.com/Batyachelly/goBoard/internal/logger"
+	"github.com/Batyachelly/goBoard/internal/privacy"
+	"github.com/Batyachelly/goBoard/internal/transport/http"
+	"github.com/Batyachelly/goBoard/internal/usecase"
+	"github.com/Batyachelly/goBoard/pkg/client"
+	"github.com/Batyachelly/goBoard/pkg/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+const moderatorToken = "secret"
+
+// serve starts the API with the memory backend behind wrap, which may be nil,
+// and returns its URL.
+func serve(t *testing.T, wrap func(nethttp.Handler) nethttp.Handler) string {
+	t.Helper()
+
+	ips, err := privacy.NewIPProtector(privacy.Config{
+		HashKey:       "secret",
+		EncryptionKey: strings.Repeat("ab", 32),
+	})
+	require.NoError(t, err)
+
+	uc := usecase.NewUsecase(memory.NewDatabaseService(memory.Config{Boards: []string{"General", "Random"}}), usecase.Config{
+		IPProtector:    ips,
+		IPRetention:    time.Hour,
+		DeletionWindow: time.Hour,
+	})
+
+	s := http.NewServer(http.Config{
+		Log:             logger.TestLogger{},
+		PostMaxBodySize: 1024,
+		ModeratorTokens: []string{"alice:" + moderatorToken},
+	}, uc)
+
+	handler := s.Handler()
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	return ts.URL
+}
+
+func newClient(t *testing.T, cfg client.Config) *client.Client {
+	t.Helper()
+
+	cfg.MinBackoff = time.Millisecond
+	cfg.MaxBackoff = 10 * time.Millisecond
+
+	c, err := client.New(cfg)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := client.New(client.Config{BaseURL: "board.example.com"})
+	require.Error(t, err)
+
+	_, err = client.New(client.Config{BaseURL: "https://board.example.com/"})
+	require.NoError(t, err)
+}
+
+func TestClient_Threads(t *testing.T) {
+	t.Parallel()
+
+	c := newClient(t, client.Config{BaseURL: serve(t, nil)})
+	ctx := context.Background()
+
+	require.NoError(t, c.Healthz(ctx))
+	require.NoError(t, c.Readyz(ctx))
+
+	boards, err := c.GetBoards(ctx)
+	require.NoError(t, err)
+	require.Len(t, boards, 2)
+	require.Equal(t, "General", boards[0].Title)
+
+	thread, err := c.PostThread(ctx, 1, data.PostThreadRequest{Title: "Title", Text: "Text"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), thread.ThreadID)
+
+	reply, err := c.PostMessage(ctx, 1, thread.ThreadID, data.PostMessageRequest{Text: "Reply", Sage: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, reply.DeletionToken)
+
+	board, err := c.GetBoard(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "General", board.Title)
+	require.NotEmpty(t, board.Threads)
+
+	messages, err := c.GetThread(ctx, 1, thread.ThreadID, client.ThreadOptions{})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.True(t, messages[1].Sage)
+
+	messages, err = c.GetThread(ctx, 1, thread.ThreadID, client.ThreadOptions{Since: messages[0].ID})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, reply.MessageID, messages[0].ID)
+
+	require.NoError(t, c.DeleteMessage(ctx, 1, reply.MessageID, data.DeleteMessageRequest{Password: reply.DeletionToken}))
+
+	messages, err = c.GetThread(ctx, 1, thread.ThreadID, client.ThreadOptions{})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+}
+
+func TestClient_Moderation(t *testing.T) {
+	t.Parallel()
+
+	url := serve(t, nil)
+	c := newClient(t, client.Config{BaseURL: url, ModeratorToken: moderatorToken})
+	ctx := context.Background()
+
+	thread, err := c.PostThread(ctx, 1, data.PostThreadRequest{Title: "Title", Text: "Text"})
+	require.NoError(t, err)
+
+	reply, err := c.PostMessage(ctx, 1, thread.ThreadID, data.PostMessageRequest{Text: "Spam"})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = c.PostReport(ctx, 1, reply.MessageID, data.PostReportRequest{Reason: "spam"})
+		require.NoError(t, err)
+	}
+
+	dismissed, err := c.PostReport(ctx, 1, thread.ThreadID, data.PostReportRequest{Reason: "spam"})
+	require.NoError(t, err)
+	require.NoError(t, c.DismissReport(ctx, dismissed.ReportID))
+
+	reports, err := c.GetReports(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	reports, err = c.GetReports(ctx, client.ReportDismissed)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, dismissed.ReportID, reports[0].ID)
+
+	groups, err := c.GetReportGroups(ctx, client.ReportOpen)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Reports, 2)
+
+	require.NoError(t, c.ActOnReport(ctx, groups[0].Reports[0].ID))
+
+	author, err := c.GetMessageAuthor(ctx, 1, thread.ThreadID)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1", author.IP)
+
+	ban, err := c.PostMessageBan(ctx, 1, thread.ThreadID, data.PostMessageBanRequest{Reason: "spam"})
+	require.NoError(t, err)
+
+	_, err = c.PostBan(ctx, data.PostBanRequest{Network: "10.0.0.0/8", Reason: "spam", Duration: "1h"})
+	require.NoError(t, err)
+
+	bans, err := c.GetBans(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 2)
+
+	require.NoError(t, c.DeleteBan(ctx, ban.BanID))
+
+	bans, err = c.GetBans(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+
+	_, err = newClient(t, client.Config{BaseURL: url}).GetBans(ctx)
+	require.Equal(t, nethttp.StatusUnauthorized, client.StatusCode(err), err)
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Parallel()
+
+	c := newClient(t, client.Config{BaseURL: serve(t, nil), ModeratorToken: moderatorToken})
+	ctx := context.Background()
+
+	_, err := c.PostBan(ctx, data.PostBanRequest{Network: "127.0.0.1", BoardID: 2, Reason: "spam"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantStatus int
+		wantCode   string
+		wantBan    bool
+	}{
+		{
+			name: "unknown message",
+			call: func() error {
+				return c.DeleteMessage(ctx, 1, 9, data.DeleteMessageRequest{Password: "secret"})
+			},
+			wantStatus: nethttp.StatusNotFound,
+		},
+		{
+			name: "body too large",
+			call: func() error {
+				_, err := c.PostThread(ctx, 1, data.PostThreadRequest{Title: "Title", Text: string(make([]byte, 2048))})
+
+				return err
+			},
+			wantStatus: nethttp.StatusRequestEntityTooLarge,
+			wantCode:   "body_too_large",
+		},
+		{
+			name: "banned",
+			call: func() error {
+				_, err := c.PostThread(ctx, 2, data.PostThreadRequest{Title: "Title", Text: "Text"})
+
+				return err
+			},
+			wantStatus: nethttp.StatusForbidden,
+			wantBan:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.call()
+
+			var apiErr *client.Error
+			require.True(t, errors.As(err, &apiErr), err)
+			require.Equal(t, tt.wantStatus, apiErr.StatusCode)
+			require.Equal(t, tt.wantCode, apiErr.Code)
+			require.Equal(t, tt.wantBan, apiErr.Ban != nil)
+		})
+	}
+}
+
+// failing answers the first fails requests with status, asking to retry
+// at once when rate limiting.
+func failing(fails int32, status int, calls *int32) func(nethttp.Handler) nethttp.Handler {
+	return func(next nethttp.Handler) nethttp.Handler {
+		return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			if atomic.AddInt32(calls, 1) <= fails {
+				if status == nethttp.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "0")
+				}
+
+				w.WriteHeader(status)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	t.Parallel()
+
+	getBoards := func(c *client.Client) error {
+		_, err := c.GetBoards(context.Background())
+
+		return err
+	}
+
+	postThread := func(c *client.Client) error {
+		_, err := c.PostThread(context.Background(), 1, data.PostThreadRequest{Title: "Title", Text: "Text"})
+
+		return err
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		fails      int32
+		maxRetries int
+		call       func(c *client.Client) error
+		wantStatus int
+		wantCalls  int32
+	}{
+		{
+			name:      "server error",
+			status:    nethttp.StatusServiceUnavailable,
+			fails:     2,
+			call:      getBoards,
+			wantCalls: 3,
+		},
+		{
+			name:       "retries exhausted",
+			status:     nethttp.StatusInternalServerError,
+			fails:      5,
+			call:       getBoards,
+			wantStatus: nethttp.StatusInternalServerError,
+			wantCalls:  4,
+		},
+		{
+			name:       "retries off",
+			status:     nethttp.StatusBadGateway,
+			fails:      1,
+			maxRetries: -1,
+			call:       getBoards,
+			wantStatus: nethttp.StatusBadGateway,
+			wantCalls:  1,
+		},
+		{
+			name:       "server error on post",
+			status:     nethttp.StatusInternalServerError,
+			fails:      1,
+			call:       postThread,
+			wantStatus: nethttp.StatusInternalServerError,
+			wantCalls:  1,
+		},
+		{
+			name:      "rate limited post",
+			status:    nethttp.StatusTooManyRequests,
+			fails:     1,
+			call:      postThread,
+			wantCalls: 2,
+		},
+		{
+			name:       "not found",
+			status:     nethttp.StatusNotFound,
+			fails:      1,
+			call:       getBoards,
+			wantStatus: nethttp.StatusNotFound,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+
+			c := newClient(t, client.Config{
+				BaseURL:    serve(t, failing(tt.fails, tt.status, &calls)),
+				MaxRetries: tt.maxRetries,
+			})
+
+			err := tt.call(c)
+			require.Equal(t, tt.wantStatus, client.StatusCode(err), err)
+			require.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+
+			if tt.wantStatus == 0 {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_RetryCanceled(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	c, err := client.New(client.Config{
+		BaseURL:    serve(t, failing(5, nethttp.StatusServiceUnavailable, &calls)),
+		MinBackoff: time.Hour,
+		MaxBackoff: time.Hour,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.GetThread(ctx, 1, 1, client.ThreadOptions{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestThreadIterator(t *testing.T) {
+	t.Parallel()
+
+	c := newClient(t, client.Config{BaseURL: serve(t, nil)})
+	ctx := context.Background()
+
+	thread, err := c.PostThread(ctx, 1, data.PostThreadRequest{Title: "Title", Text: "Text"})
+	require.NoError(t, err)
+
+	for _, text := range []string{"First", "Second"} {
+		_, err := c.PostMessage(ctx, 1, thread.ThreadID, data.PostMessageRequest{Text: text})
+		require.NoError(t, err)
+	}
+
+	var texts []string
+
+	it := c.ThreadIterator(1, thread.ThreadID, client.ThreadOptions{})
+	for it.Next(ctx) {
+		texts = append(texts, it.Message().Text)
+	}
+
+	require.NoError(t, it.Err())
+	require.Equal(t, []string{"Text", "First", "Second"}, texts)
+
+	// Following the thread waits for the next message.
+	follow := c.ThreadIterator(1, thread.ThreadID, client.ThreadOptions{Since: it.Since(), Wait: 5 * time.Second})
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+
+		_, _ = c.PostMessage(ctx, 1, thread.ThreadID, data.PostMessageRequest{Text: "Third"})
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	require.True(t, follow.Next(waitCtx), follow.Err())
+	require.Equal(t, "Third", follow.Message().Text)
+
+	canceledCtx, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+
+	require.False(t, follow.Next(canceledCtx))
+	require.ErrorIs(t, follow.Err(), context.Canceled)
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Batyachelly/goBoard/pkg/data"
+)
+
+// ThreadIterator pages through a thread by message ID, each page holding
+// the messages after the last one read. Without a wait it stops at the end
+// of the thread, with one it long-polls for new messages, following the
+// thread until the context is done.
+//
+//	it := c.ThreadIterator(boardID, threadID, client.ThreadOptions{})
+//	for it.Next(ctx) {
+//		m := it.Message()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ThreadIterator struct {
+	client   *Client
+	boardID  uint64
+	threadID uint64
+	opts     ThreadOptions
+
+	page    data.GetThread
+	message data.Message
+	err     error
+}
+
+// ThreadIterator reads the thread from opts.Since on, the whole thread if
+// it is zero.
+func (c *Client) ThreadIterator(boardID, threadID uint64, opts ThreadOptions) *ThreadIterator {
+	return &ThreadIterator{
+		client:   c,
+		boardID:  boardID,
+		threadID: threadID,
+		opts:     opts,
+	}
+}
+
+// Next advances to the next message, fetching the next page when the
+// current one is read. It returns false at the end of the thread or on an
+// error, which Err returns.
+func (it *ThreadIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.err != nil {
+			return false
+		}
+
+		page, err := it.client.GetThread(ctx, it.boardID, it.threadID, it.opts)
+		if err != nil {
+			it.err = err
+
+			return false
+		}
+
+		if len(page) == 0 && it.opts.Wait == 0 {
+			return false
+		}
+
+		it.page = page
+	}
+
+	it.message, it.page = it.page[0], it.page[1:]
+
+	if it.message.ID > it.opts.Since {
+		it.opts.Since = it.message.ID
+	}
+
+	return true
+}
+
+// Message is the message Next advanced to.
+func (it *ThreadIterator) Message() data.Message {
+	return it.message
+}
+
+// Since is the ID of the last message read, to resume from.
+func (it *ThreadIterator) Since() uint64 {
+	return it.opts.Since
+}
+
+func (it *ThreadIterator) Err() error {
+	return it.err
+}
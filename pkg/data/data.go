@@ -0,0 +1,135 @@
+package data
+
+import "time"
+
+type GetBoardResponse struct {
+	ID    uint64 `json:"id"`
+	Title string `json:"title"`
+
+	Threads GetThread `json:"threads,omitempty"`
+}
+
+type Message struct {
+	ID       uint64    `json:"id"`
+	Title    string    `json:"title"`
+	Text     string    `json:"text"`
+	Content  string    `json:"content"`
+	Created  time.Time `json:"created"`
+	PosterID string    `json:"posterId,omitempty"`
+	Sage     bool      `json:"sage,omitempty"`
+}
+
+type GetBoardsResponse []GetBoardResponse
+
+type GetThread []Message
+
+type PostThreadRequest struct {
+	Title    string `json:"title"`
+	Text     string `json:"text"`
+	Content  string `json:"content"`
+	Password string `json:"password,omitempty"`
+}
+
+type PostThreadResponse struct {
+	ThreadID      uint64 `json:"threadId"`
+	DeletionToken string `json:"deletionToken,omitempty"`
+}
+
+type PostMessageRequest struct {
+	Title    string `json:"title"`
+	Text     string `json:"text"`
+	Content  string `json:"content"`
+	Password string `json:"password,omitempty"`
+	Sage     bool   `json:"sage,omitempty"`
+	// Email set to "sage" is the classic way to post without bumping.
+	Email string `json:"email,omitempty"`
+}
+
+type PostMessageResponse struct {
+	MessageID     uint64 `json:"messageId"`
+	DeletionToken string `json:"deletionToken,omitempty"`
+}
+
+type DeleteMessageRequest struct {
+	Password    string `json:"password"`
+	ContentOnly bool   `json:"contentOnly"`
+}
+
+type PostReportRequest struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment"`
+}
+
+type PostReportResponse struct {
+	ReportID uint64 `json:"reportId"`
+}
+
+type Report struct {
+	ID        uint64     `json:"id"`
+	BoardID   uint64     `json:"boardId"`
+	MessageID uint64     `json:"messageId"`
+	Reason    string     `json:"reason"`
+	Comment   string     `json:"comment"`
+	Status    string     `json:"status"`
+	Created   time.Time  `json:"created"`
+	Resolved  *time.Time `json:"resolved,omitempty"`
+}
+
+type ReportGroup struct {
+	BoardID   uint64   `json:"boardId"`
+	MessageID uint64   `json:"messageId"`
+	Reports   []Report `json:"reports"`
+}
+
+type GetReportsResponse []Report
+
+type GetReportGroupsResponse []ReportGroup
+
+type BannedResponse struct {
+	BanID   uint64     `json:"banId"`
+	BoardID uint64     `json:"boardId,omitempty"`
+	Reason  string     `json:"reason"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type PostBanRequest struct {
+	Network  string `json:"network"`
+	IPHash   string `json:"ipHash"`
+	BoardID  uint64 `json:"boardId"`
+	Reason   string `json:"reason"`
+	Duration string `json:"duration"`
+}
+
+type PostMessageBanRequest struct {
+	Reason   string `json:"reason"`
+	Duration string `json:"duration"`
+	Global   bool   `json:"global"`
+}
+
+type PostBanResponse struct {
+	BanID uint64 `json:"banId"`
+}
+
+type Ban struct {
+	ID        uint64     `json:"id"`
+	BoardID   uint64     `json:"boardId,omitempty"`
+	Network   string     `json:"network,omitempty"`
+	IPHash    string     `json:"ipHash,omitempty"`
+	Reason    string     `json:"reason"`
+	Moderator string     `json:"moderator"`
+	Created   time.Time  `json:"created"`
+	Expires   *time.Time `json:"expires,omitempty"`
+}
+
+type GetBansResponse []Ban
+
+type MessageAuthorResponse struct {
+	IP     string `json:"ip,omitempty"`
+	IPHash string `json:"ipHash"`
+}
+
+// ErrorResponse describes why a request was rejected.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}